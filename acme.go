@@ -0,0 +1,60 @@
+package libdns
+
+import (
+	"strings"
+	"time"
+)
+
+// acmeChallengeLabel is the label prepended to a domain name to form
+// the name of its ACME DNS-01 challenge record (RFC 8555 section 8.4).
+const acmeChallengeLabel = "_acme-challenge"
+
+// NewACMEChallenges returns one TXT record per token for the ACME
+// DNS-01 challenge of the domain with the (relative) name, all with
+// the given TTL. All records have the same name, so they form a
+// single RRset; this is needed, for example, when a certificate covers
+// both a name and its wildcard.
+//
+// A leading wildcard label is removed from name, since the challenge
+// for "*.sub" is placed at "_acme-challenge.sub", like the one for
+// "sub" itself. An empty name or "@" denotes the zone apex.
+func NewACMEChallenges(name string, tokens []string, ttl time.Duration) []Record {
+	name = strings.TrimPrefix(name, "*.")
+	if name == "*" {
+		name = ""
+	}
+	challengeName := acmeChallengeLabel
+	if name != "" && name != "@" {
+		challengeName += "." + name
+	}
+
+	recs := make([]Record, 0, len(tokens))
+	for _, token := range tokens {
+		recs = append(recs, Record{
+			Type:  "TXT",
+			Name:  challengeName,
+			Value: token,
+			TTL:   ttl,
+		})
+	}
+	return recs
+}
+
+// ACMEChallengeValue returns the text of r and true if r is an ACME
+// DNS-01 challenge record: a TXT record whose name, relative or
+// fully-qualified, has "_acme-challenge" as its leftmost label. This is
+// useful for finding stale challenge records to clean up. If r is not
+// a challenge record, it returns false.
+func ACMEChallengeValue(r Record) (string, bool) {
+	if !strings.EqualFold(r.Type, "TXT") {
+		return "", false
+	}
+	label := r.Name
+	if dot := strings.Index(label, "."); dot >= 0 {
+		label = label[:dot]
+	}
+	if !strings.EqualFold(label, acmeChallengeLabel) {
+		return "", false
+	}
+	return r.Value, true
+}
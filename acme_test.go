@@ -0,0 +1,74 @@
+package libdns
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewACMEChallenges(t *testing.T) {
+	for i, test := range []struct {
+		name       string
+		expectName string
+	}{
+		{name: "", expectName: "_acme-challenge"},
+		{name: "@", expectName: "_acme-challenge"},
+		{name: "*", expectName: "_acme-challenge"},
+		{name: "sub", expectName: "_acme-challenge.sub"},
+		{name: "*.sub", expectName: "_acme-challenge.sub"},
+	} {
+		tokens := []string{"token-for-name", "token-for-wildcard"}
+		recs := NewACMEChallenges(test.name, tokens, 2*time.Minute)
+		if len(recs) != len(tokens) {
+			t.Errorf("Test %d: expected %d records but got %d", i, len(tokens), len(recs))
+			continue
+		}
+		for j, rec := range recs {
+			expect := Record{Type: "TXT", Name: test.expectName, Value: tokens[j], TTL: 2 * time.Minute}
+			if rec != expect {
+				t.Errorf("Test %d: record %d: expected %+v but got %+v", i, j, expect, rec)
+			}
+		}
+		if rrsets := GroupByRRSet(recs); len(rrsets) != 1 {
+			t.Errorf("Test %d: expected records to form 1 RRset but got %d", i, len(rrsets))
+		}
+	}
+}
+
+func TestACMEChallengeValue(t *testing.T) {
+	for i, test := range []struct {
+		rec      Record
+		expect   string
+		expectOK bool
+	}{
+		{
+			rec:      Record{Type: "TXT", Name: "_acme-challenge", Value: "token"},
+			expect:   "token",
+			expectOK: true,
+		},
+		{
+			rec:      Record{Type: "txt", Name: "_ACME-Challenge.sub", Value: "token"},
+			expect:   "token",
+			expectOK: true,
+		},
+		{
+			rec:      Record{Type: "TXT", Name: "_acme-challenge.example.com.", Value: "token"},
+			expect:   "token",
+			expectOK: true,
+		},
+		{
+			rec: Record{Type: "TXT", Name: "@", Value: "v=spf1 -all"},
+		},
+		{
+			rec: Record{Type: "TXT", Name: "sub._acme-challenge", Value: "token"},
+		},
+		{
+			rec: Record{Type: "CNAME", Name: "_acme-challenge", Value: "challenges.example.net."},
+		},
+	} {
+		actual, ok := ACMEChallengeValue(test.rec)
+		if actual != test.expect || ok != test.expectOK {
+			t.Errorf("Test %d: for %+v expected (%q, %t) but got (%q, %t)",
+				i, test.rec, test.expect, test.expectOK, actual, ok)
+		}
+	}
+}
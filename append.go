@@ -0,0 +1,49 @@
+package libdns
+
+import (
+	"context"
+	"fmt"
+)
+
+// AppendUnique ensures that the records in recs are present in zone. It
+// gets the zone's records with getter, then uses appender to append only
+// the records in recs that do not already exist, as compared by
+// RecordsEqual; records that are repeated in recs are appended once. It
+// returns the records that were appended, which is empty (and appender
+// is not called) if all of recs already exist.
+//
+// This is safe to call repeatedly with the same input, unlike
+// AppendRecords, which may fail or add duplicate records, depending on
+// the provider. Note that the zone may still change between getting and
+// appending the records.
+func AppendUnique(ctx context.Context, appender RecordAppender, getter RecordGetter, zone string, recs []Record) ([]Record, error) {
+	if len(recs) == 0 {
+		return nil, nil
+	}
+	existing, err := getter.GetRecords(ctx, zone)
+	if err != nil {
+		return nil, fmt.Errorf("getting records: %w", err)
+	}
+
+	var toAppend []Record
+	for _, rec := range recs {
+		if !containsRecord(existing, rec, zone) && !containsRecord(toAppend, rec, zone) {
+			toAppend = append(toAppend, rec)
+		}
+	}
+	if len(toAppend) == 0 {
+		return nil, nil
+	}
+	return appender.AppendRecords(ctx, zone, toAppend)
+}
+
+// containsRecord reports whether recs contains a record equal to rec,
+// as compared by RecordsEqual.
+func containsRecord(recs []Record, rec Record, zone string) bool {
+	for _, r := range recs {
+		if RecordsEqual(r, rec, zone) {
+			return true
+		}
+	}
+	return false
+}
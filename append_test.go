@@ -0,0 +1,64 @@
+package libdns_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/libdns/libdns/memory"
+)
+
+func TestAppendUnique(t *testing.T) {
+	ctx := context.Background()
+	const zone = "example.com."
+	var p memory.Provider
+
+	_, err := p.AppendRecords(ctx, zone, []libdns.Record{
+		{Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "TXT", Name: "@", Value: "hello", TTL: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	// partial overlap: one record exists (by a different name format),
+	// one is new, and one is repeated in the input
+	recs := []libdns.Record{
+		{Type: "A", Name: "www.example.com.", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "A", Name: "www", Value: "192.0.2.2", TTL: time.Hour},
+		{Type: "A", Name: "www", Value: "192.0.2.2", TTL: time.Hour},
+	}
+	appended, err := libdns.AppendUnique(ctx, &p, &p, zone, recs)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(appended) != 1 || appended[0].Value != "192.0.2.2" {
+		t.Errorf("Expected only the new record to be appended but got %+v", appended)
+	}
+
+	// appending again is a no-op
+	appended, err = libdns.AppendUnique(ctx, &p, &p, zone, recs)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(appended) != 0 {
+		t.Errorf("Expected no records to be appended but got %+v", appended)
+	}
+
+	got, _ := p.GetRecords(ctx, zone)
+	if len(got) != 3 {
+		t.Errorf("Expected 3 records in zone but got %+v", got)
+	}
+}
+
+func TestAppendUniqueUnknownZone(t *testing.T) {
+	var p memory.Provider
+	_, err := libdns.AppendUnique(context.Background(), &p, &p, "example.net.", []libdns.Record{
+		{Type: "A", Name: "www", Value: "192.0.2.1"},
+	})
+	if !errors.Is(err, libdns.ErrZoneNotFound) {
+		t.Errorf("Expected ErrZoneNotFound but got: %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package libdns
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// RecordsEqual reports whether a and b describe the same DNS record in
+// zone. Provider-specific fields like ID are ignored, and fields are
+// compared semantically rather than literally:
+//
+//   - Types are compared case-insensitively.
+//   - Names are resolved against zone, so a relative name and the
+//     equivalent fully-qualified name (with trailing dot) are equal.
+//   - Domain names in values, such as CNAME and MX targets, are compared
+//     case-insensitively and regardless of a trailing dot.
+//   - IP addresses in A and AAAA records are compared as addresses.
+func RecordsEqual(a, b Record, zone string) bool {
+	if !strings.EqualFold(a.Type, b.Type) ||
+		!namesEqual(qualifiedName(a.Name, zone), qualifiedName(b.Name, zone)) ||
+		a.TTL != b.TTL {
+		return false
+	}
+
+	if a.Type == "MX" && b.Type == "MX" {
+		mxA, errA := a.ToMX()
+		mxB, errB := b.ToMX()
+		if errA == nil && errB == nil {
+			return MXEqual(mxA, mxB)
+		}
+	}
+
+	return a.Priority == b.Priority &&
+		a.Weight == b.Weight &&
+		valuesEqual(strings.ToUpper(a.Type), a.Value, b.Value)
+}
+
+// qualifiedName returns name as a FQDN within zone. Names that already
+// end with a dot are considered to be fully-qualified.
+func qualifiedName(name, zone string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return AbsoluteName(name, zone)
+}
+
+// namesEqual compares two domain names case-insensitively, ignoring
+// whether they end with a dot.
+func namesEqual(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
+
+// valuesEqual compares the Values of two records of type typ.
+func valuesEqual(typ, a, b string) bool {
+	switch typ {
+	case "A", "AAAA":
+		ipA, errA := netip.ParseAddr(a)
+		ipB, errB := netip.ParseAddr(b)
+		if errA == nil && errB == nil {
+			return ipA == ipB
+		}
+
+	case "CNAME", "DNAME", "NS", "PTR", "ALIAS", "MX":
+		return namesEqual(strings.TrimSpace(a), strings.TrimSpace(b))
+
+	case "SRV":
+		// "<port> <target>"
+		fieldsA, fieldsB := strings.Fields(a), strings.Fields(b)
+		if len(fieldsA) == 2 && len(fieldsB) == 2 {
+			return fieldsA[0] == fieldsB[0] && namesEqual(fieldsA[1], fieldsB[1])
+		}
+
+	case "CAA":
+		// "<flags> <tag> <value>", where the value may or may not be quoted
+		flagsA, tagA, valueA, okA := splitCAA(a)
+		flagsB, tagB, valueB, okB := splitCAA(b)
+		if okA && okB {
+			return flagsA == flagsB &&
+				strings.EqualFold(tagA, tagB) &&
+				caaValuesEqual(valueA, valueB)
+		}
+	}
+
+	return a == b
+}
+
+// caaValuesEqual compares the values of two CAA records, which may or
+// may not be quoted.
+func caaValuesEqual(a, b string) bool {
+	unquotedA, _ := unquoteCAAValue(a)
+	unquotedB, _ := unquoteCAAValue(b)
+	return unquotedA == unquotedB
+}
+
+// TypeReclassified reports whether a provider stored the input record
+// as a different type than requested, as indicated by the type of the
+// record it returned; for example, an SPF record that came back as TXT.
+func TypeReclassified(input, returned Record) bool {
+	return !strings.EqualFold(input.Type, returned.Type)
+}
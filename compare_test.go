@@ -0,0 +1,146 @@
+package libdns
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordsEqual(t *testing.T) {
+	for i, test := range []struct {
+		a, b   Record
+		zone   string
+		expect bool
+	}{
+		{
+			a:      Record{Type: "A", Name: "www", Value: "1.2.3.4", TTL: time.Minute},
+			b:      Record{Type: "A", Name: "www", Value: "1.2.3.4", TTL: time.Minute, ID: "123"},
+			zone:   "example.com.",
+			expect: true,
+		},
+		{
+			a:      Record{Type: "A", Name: "www", Value: "1.2.3.4"},
+			b:      Record{Type: "a", Name: "www.example.com.", Value: "1.2.3.4"},
+			zone:   "example.com.",
+			expect: true,
+		},
+		{
+			a:      Record{Type: "A", Name: "www", Value: "1.2.3.4"},
+			b:      Record{Type: "A", Name: "www", Value: "1.2.3.5"},
+			zone:   "example.com.",
+			expect: false,
+		},
+		{
+			a:      Record{Type: "A", Name: "www", Value: "1.2.3.4", TTL: time.Minute},
+			b:      Record{Type: "A", Name: "www", Value: "1.2.3.4", TTL: time.Hour},
+			zone:   "example.com.",
+			expect: false,
+		},
+		{
+			a:      Record{Type: "AAAA", Name: "@", Value: "2001:db8::1"},
+			b:      Record{Type: "AAAA", Name: "", Value: "2001:0db8:0:0::1"},
+			zone:   "example.com.",
+			expect: true,
+		},
+		{
+			a:      Record{Type: "CNAME", Name: "www", Value: "example.com."},
+			b:      Record{Type: "CNAME", Name: "WWW", Value: "Example.com"},
+			zone:   "example.com.",
+			expect: true,
+		},
+		{
+			a:      Record{Type: "CNAME", Name: "www", Value: "example.com."},
+			b:      Record{Type: "CNAME", Name: "www", Value: "example.net."},
+			zone:   "example.com.",
+			expect: false,
+		},
+		{
+			a:      Record{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10},
+			b:      Record{Type: "MX", Name: "@", Value: "mail.example.com", Priority: 10},
+			zone:   "example.com.",
+			expect: true,
+		},
+		{
+			a:      Record{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10},
+			b:      Record{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 20},
+			zone:   "example.com.",
+			expect: false,
+		},
+		{
+			a:      Record{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 10, Weight: 5},
+			b:      Record{Type: "SRV", Name: "_sip._tcp.example.com.", Value: "5060 sip.example.com", Priority: 10, Weight: 5},
+			zone:   "example.com.",
+			expect: true,
+		},
+		{
+			a:      Record{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 10, Weight: 5},
+			b:      Record{Type: "SRV", Name: "_sip._tcp", Value: "5061 sip.example.com.", Priority: 10, Weight: 5},
+			zone:   "example.com.",
+			expect: false,
+		},
+		{
+			a:      Record{Type: "TXT", Name: "@", Value: "v=spf1 -all"},
+			b:      Record{Type: "TXT", Name: "@", Value: "v=spf1 -all"},
+			zone:   "example.com.",
+			expect: true,
+		},
+		{
+			a:      Record{Type: "TXT", Name: "@", Value: "Hello"},
+			b:      Record{Type: "TXT", Name: "@", Value: "hello"},
+			zone:   "example.com.",
+			expect: false,
+		},
+		{
+			a:      Record{Type: "CAA", Name: "@", Value: `0 issue "letsencrypt.org"`},
+			b:      Record{Type: "CAA", Name: "@", Value: `0 ISSUE letsencrypt.org`},
+			zone:   "example.com.",
+			expect: true,
+		},
+		{
+			a:      Record{Type: "CAA", Name: "@", Value: `0 issue "letsencrypt.org"`},
+			b:      Record{Type: "CAA", Name: "@", Value: `128 issue "letsencrypt.org"`},
+			zone:   "example.com.",
+			expect: false,
+		},
+		{
+			a:      Record{Type: "CAA", Name: "@", Value: `0 issue "letsencrypt.org"`},
+			b:      Record{Type: "CAA", Name: "@", Value: "0  issue\t\"letsencrypt.org\""},
+			zone:   "example.com.",
+			expect: true,
+		},
+	} {
+		actual := RecordsEqual(test.a, test.b, test.zone)
+		if actual != test.expect {
+			t.Errorf("Test %d: A=%+v B=%+v ZONE=%s - expected %t but got %t",
+				i, test.a, test.b, test.zone, test.expect, actual)
+		}
+	}
+}
+
+func TestTypeReclassified(t *testing.T) {
+	for i, test := range []struct {
+		input, returned Record
+		expect          bool
+	}{
+		{
+			input:    Record{Type: "SPF", Name: "@", Value: "v=spf1 -all"},
+			returned: Record{Type: "TXT", Name: "@", Value: "v=spf1 -all", ID: "1"},
+			expect:   true,
+		},
+		{
+			input:    Record{Type: "A", Name: "www", Value: "::1"},
+			returned: Record{Type: "AAAA", Name: "www", Value: "::1", ID: "2"},
+			expect:   true,
+		},
+		{
+			input:    Record{Type: "TXT", Name: "@", Value: "hello"},
+			returned: Record{Type: "txt", Name: "@", Value: "hello", ID: "3"},
+			expect:   false,
+		},
+	} {
+		actual := TypeReclassified(test.input, test.returned)
+		if actual != test.expect {
+			t.Errorf("Test %d: input type %s, returned type %s - expected %t but got %t",
+				i, test.input.Type, test.returned.Type, test.expect, actual)
+		}
+	}
+}
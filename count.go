@@ -0,0 +1,17 @@
+package libdns
+
+import "context"
+
+// CountRecords returns the number of records in zone. If g implements
+// RecordCounter, its CountRecords method is used; otherwise, all the
+// records are retrieved with GetRecords and counted.
+func CountRecords(ctx context.Context, g RecordGetter, zone string) (int, error) {
+	if c, ok := g.(RecordCounter); ok {
+		return c.CountRecords(ctx, zone)
+	}
+	recs, err := g.GetRecords(ctx, zone)
+	if err != nil {
+		return 0, err
+	}
+	return len(recs), nil
+}
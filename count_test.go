@@ -0,0 +1,44 @@
+package libdns
+
+import (
+	"context"
+	"testing"
+)
+
+type countingGetter struct {
+	staticGetter
+}
+
+func (countingGetter) CountRecords(ctx context.Context, zone string) (int, error) {
+	return 500, nil
+}
+
+func TestCountRecords(t *testing.T) {
+	g := &staticGetter{recs: []Record{
+		{Type: "A", Name: "www", Value: "192.0.2.1"},
+		{Type: "A", Name: "www", Value: "192.0.2.2"},
+		{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10},
+	}}
+	count, err := CountRecords(context.Background(), g, "example.com.")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 records but got %d", count)
+	}
+	if g.calls != 1 {
+		t.Errorf("Expected 1 call to GetRecords but got %d", g.calls)
+	}
+
+	c := &countingGetter{}
+	count, err = CountRecords(context.Background(), c, "example.com.")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if count != 500 {
+		t.Errorf("Expected count from RecordCounter (500) but got %d", count)
+	}
+	if c.calls != 0 {
+		t.Errorf("Expected GetRecords not to be called but it was called %d times", c.calls)
+	}
+}
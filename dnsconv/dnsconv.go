@@ -0,0 +1,58 @@
+// Package dnsconv converts between libdns records and the resource
+// records of github.com/miekg/dns, for programs that bridge libdns
+// providers with DNS servers or clients built on that package.
+//
+// It is a separate module so that the libdns package itself remains
+// free of dependencies. To develop it against a local copy of libdns,
+// create a workspace at the root of the repository with:
+//
+//	go work init . ./dnsconv
+package dnsconv
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/libdns/libdns"
+	"github.com/miekg/dns"
+)
+
+// ToMiekg converts r, whose name is relative to zone, to a dns.RR with
+// a fully-qualified owner name. Relative domain names in the record data
+// are also qualified with zone.
+//
+// The conversion goes through the zone file presentation format, so any
+// type known to both packages is supported.
+func ToMiekg(r libdns.Record, zone string) (dns.RR, error) {
+	data, err := libdns.MarshalZone(zone, []libdns.Record{r})
+	if err != nil {
+		return nil, err
+	}
+	zp := dns.NewZoneParser(bytes.NewReader(data), "", "")
+	rr, ok := zp.Next()
+	if err := zp.Err(); err != nil {
+		return nil, fmt.Errorf("parsing %s record %q: %v", r.Type, r.Name, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("no record parsed for %s record %q", r.Type, r.Name)
+	}
+	return rr, nil
+}
+
+// FromMiekg converts rr to a record with a name relative to zone; the
+// zone apex is named "@". It returns an error if rr is not in zone.
+func FromMiekg(rr dns.RR, zone string) (libdns.Record, error) {
+	if rr == nil {
+		return libdns.Record{}, fmt.Errorf("nil resource record")
+	}
+	origin := dns.Fqdn(zone)
+	input := fmt.Sprintf("$ORIGIN %s\n%s\n", origin, rr.String())
+	_, recs, err := libdns.UnmarshalZone([]byte(input))
+	if err != nil {
+		return libdns.Record{}, err
+	}
+	if len(recs) != 1 {
+		return libdns.Record{}, fmt.Errorf("expected 1 record but got %d", len(recs))
+	}
+	return recs[0], nil
+}
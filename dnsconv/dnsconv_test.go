@@ -0,0 +1,75 @@
+package dnsconv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/miekg/dns"
+)
+
+func TestRoundTrip(t *testing.T) {
+	const zone = "example.com."
+
+	for i, test := range []struct {
+		rec    libdns.Record
+		expect string // presentation format of the dns.RR
+	}{
+		{
+			rec:    libdns.Record{Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+			expect: "www.example.com.\t3600\tIN\tA\t192.0.2.1",
+		},
+		{
+			rec:    libdns.Record{Type: "AAAA", Name: "@", Value: "2001:db8::1", TTL: 5 * time.Minute},
+			expect: "example.com.\t300\tIN\tAAAA\t2001:db8::1",
+		},
+		{
+			rec:    libdns.Record{Type: "TXT", Name: "@", Value: `v=spf1 include:"x" -all`, TTL: time.Hour},
+			expect: "example.com.\t3600\tIN\tTXT\t\"v=spf1 include:\\\"x\\\" -all\"",
+		},
+		{
+			rec:    libdns.Record{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10, TTL: time.Hour},
+			expect: "example.com.\t3600\tIN\tMX\t10 mail.example.com.",
+		},
+		{
+			rec:    libdns.Record{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 10, Weight: 20, TTL: time.Hour},
+			expect: "_sip._tcp.example.com.\t3600\tIN\tSRV\t10 20 5060 sip.example.com.",
+		},
+		{
+			rec:    libdns.Record{Type: "CAA", Name: "@", Value: `0 issue "letsencrypt.org"`, TTL: time.Hour},
+			expect: "example.com.\t3600\tIN\tCAA\t0 issue \"letsencrypt.org\"",
+		},
+		{
+			rec:    libdns.Record{Type: "HTTPS", Name: "@", Value: `. alpn="h2,h3"`, Priority: 1, TTL: time.Hour},
+			expect: "example.com.\t3600\tIN\tHTTPS\t1 . alpn=\"h2,h3\"",
+		},
+	} {
+		rr, err := ToMiekg(test.rec, zone)
+		if err != nil {
+			t.Errorf("Test %d: ToMiekg: expected no error but got: %v", i, err)
+			continue
+		}
+		if actual := rr.String(); actual != test.expect {
+			t.Errorf("Test %d: ToMiekg:\nEXPECTED %s\nGOT      %s", i, test.expect, actual)
+		}
+
+		rec, err := FromMiekg(rr, zone)
+		if err != nil {
+			t.Errorf("Test %d: FromMiekg: expected no error but got: %v", i, err)
+			continue
+		}
+		if rec != test.rec {
+			t.Errorf("Test %d: FromMiekg:\nEXPECTED %+v\nGOT      %+v", i, test.rec, rec)
+		}
+	}
+}
+
+func TestFromMiekgOutOfZone(t *testing.T) {
+	rr, err := dns.NewRR("www.example.net. 3600 IN A 192.0.2.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FromMiekg(rr, "example.com."); err == nil {
+		t.Errorf("Expected error for record outside of zone but got none")
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/libdns/libdns/dnsconv
+
+go 1.24.0
+
+require (
+	github.com/libdns/libdns v0.3.0
+	github.com/miekg/dns v1.1.72
+)
+
+require (
+	golang.org/x/mod v0.31.0 // indirect
+	golang.org/x/net v0.48.0 // indirect
+	golang.org/x/sync v0.19.0 // indirect
+	golang.org/x/sys v0.39.0 // indirect
+	golang.org/x/tools v0.40.0 // indirect
+)
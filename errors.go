@@ -0,0 +1,21 @@
+package libdns
+
+import "errors"
+
+// Errors for common failure conditions. Providers should wrap these
+// (for example, with fmt.Errorf and the %w verb) when they are able to
+// detect the condition, so that callers can check for it with errors.Is
+// regardless of the provider.
+var (
+	// ErrZoneNotFound means the zone does not exist or is not
+	// accessible with the provider's credentials.
+	ErrZoneNotFound = errors.New("zone not found")
+
+	// ErrRecordNotFound means a record that was required to exist,
+	// such as one identified by ID, does not exist.
+	ErrRecordNotFound = errors.New("record not found")
+
+	// ErrRecordExists means a record could not be created because it,
+	// or a conflicting record, already exists.
+	ErrRecordExists = errors.New("record already exists")
+)
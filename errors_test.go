@@ -0,0 +1,50 @@
+package libdns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWrappedErrors(t *testing.T) {
+	err := fmt.Errorf("getting records for zone %q: %w", "example.com.", ErrZoneNotFound)
+	if !errors.Is(err, ErrZoneNotFound) {
+		t.Errorf("Expected wrapped error to be ErrZoneNotFound: %v", err)
+	}
+	if errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("Expected wrapped error not to be ErrRecordNotFound: %v", err)
+	}
+}
+
+type errGetter struct{ err error }
+
+func (g errGetter) GetRecords(ctx context.Context, zone string) ([]Record, error) {
+	return nil, g.err
+}
+
+type errZoneLister struct{ err error }
+
+func (zl errZoneLister) ListZones(ctx context.Context) ([]Zone, error) {
+	return nil, zl.err
+}
+
+func TestHelpersWrapErrors(t *testing.T) {
+	ctx := context.Background()
+	notFound := fmt.Errorf("example.com.: %w", ErrZoneNotFound)
+
+	_, err := VerifyExist(ctx, errGetter{notFound}, "example.com.", []Record{{Type: "A", Name: "www", Value: "192.0.2.1"}})
+	if !errors.Is(err, ErrZoneNotFound) {
+		t.Errorf("VerifyExist: expected error to be ErrZoneNotFound: %v", err)
+	}
+
+	_, _, err = RelativeToMatchingZone(ctx, errZoneLister{notFound}, "www.example.com.")
+	if !errors.Is(err, ErrZoneNotFound) {
+		t.Errorf("RelativeToMatchingZone: expected listing error to be ErrZoneNotFound: %v", err)
+	}
+
+	_, _, err = RelativeToMatchingZone(ctx, staticZoneLister{{Name: "example.net."}}, "www.example.com.")
+	if !errors.Is(err, ErrZoneNotFound) {
+		t.Errorf("RelativeToMatchingZone: expected no matching zone to be ErrZoneNotFound: %v", err)
+	}
+}
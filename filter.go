@@ -0,0 +1,56 @@
+package libdns
+
+import (
+	"context"
+	"strings"
+)
+
+// RecordFilter selects records by name and type. Empty fields match any
+// value.
+type RecordFilter struct {
+	// Name is the name of the records, relative to the zone; "@" or a
+	// fully-qualified name of the zone denotes the apex. Names are
+	// compared case-insensitively.
+	Name string
+
+	// Type is the record type, compared case-insensitively.
+	Type string
+}
+
+// FilterRecords returns the records in recs, which are records of zone,
+// that match filter. It is for providers whose APIs do not support
+// filtering, to implement RecordQuerier on top of the full list of
+// records. Names in recs and filter may be relative to zone or
+// fully-qualified.
+func FilterRecords(recs []Record, filter RecordFilter, zone string) []Record {
+	var want RRSetKey
+	if filter.Name != "" {
+		want = rrsetKeyInZone(Record{Name: filter.Name}, zone)
+	}
+	var matches []Record
+	for _, rec := range recs {
+		if filter.Name != "" && rrsetKeyInZone(rec, zone).Name != want.Name {
+			continue
+		}
+		if filter.Type != "" && !strings.EqualFold(rec.Type, filter.Type) {
+			continue
+		}
+		matches = append(matches, rec)
+	}
+	return matches
+}
+
+// QueryRecords returns the records in zone that match filter. If g
+// implements RecordQuerier, its GetRecordsMatching method is used;
+// otherwise all the records are retrieved with GetRecords and filtered
+// with FilterRecords.
+func QueryRecords(ctx context.Context, g RecordGetter, zone string, filter RecordFilter) ([]Record, error) {
+	if q, ok := g.(RecordQuerier); ok {
+		return q.GetRecordsMatching(ctx, zone, filter)
+	}
+	recs, err := g.GetRecords(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+	return FilterRecords(recs, filter, zone), nil
+}
@@ -0,0 +1,98 @@
+package libdns
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestFilterRecords(t *testing.T) {
+	recs := []Record{
+		{Type: "A", Name: "@", Value: "192.0.2.1"},
+		{Type: "TXT", Name: "_acme-challenge", Value: "token1"},
+		{Type: "TXT", Name: "_ACME-Challenge", Value: "token2"},
+		{Type: "TXT", Name: "www", Value: "hello"},
+		{Type: "CNAME", Name: "_acme-challenge.sub", Value: "elsewhere.example.net."},
+		{Type: "MX", Name: "", Value: "mail.example.com.", Priority: 10},
+	}
+
+	for i, test := range []struct {
+		filter RecordFilter
+		expect []Record
+	}{
+		{
+			filter: RecordFilter{Name: "_acme-challenge", Type: "TXT"},
+			expect: recs[1:3],
+		},
+		{
+			filter: RecordFilter{Name: "_acme-challenge.sub"},
+			expect: recs[4:5],
+		},
+		{
+			filter: RecordFilter{Type: "txt"},
+			expect: recs[1:4],
+		},
+		{
+			filter: RecordFilter{Name: "@"},
+			expect: []Record{recs[0], recs[5]},
+		},
+		{
+			filter: RecordFilter{Name: "example.com."},
+			expect: []Record{recs[0], recs[5]},
+		},
+		{
+			filter: RecordFilter{Name: "WWW.example.com.", Type: "TXT"},
+			expect: recs[3:4],
+		},
+		{
+			filter: RecordFilter{},
+			expect: recs,
+		},
+		{
+			filter: RecordFilter{Name: "nothing"},
+			expect: nil,
+		},
+	} {
+		actual := FilterRecords(recs, test.filter, "example.com.")
+		if !reflect.DeepEqual(actual, test.expect) {
+			t.Errorf("Test %d: FILTER=%+v\nEXPECTED %+v\nGOT      %+v", i, test.filter, test.expect, actual)
+		}
+	}
+}
+
+type queryingGetter struct {
+	staticGetter
+	filter RecordFilter
+}
+
+func (g *queryingGetter) GetRecordsMatching(ctx context.Context, zone string, filter RecordFilter) ([]Record, error) {
+	g.filter = filter
+	return nil, nil
+}
+
+func TestQueryRecords(t *testing.T) {
+	g := &staticGetter{recs: []Record{
+		{Type: "TXT", Name: "_acme-challenge", Value: "token1"},
+		{Type: "TXT", Name: "_acme-challenge.example.com.", Value: "token2"},
+		{Type: "TXT", Name: "www", Value: "hello"},
+	}}
+	actual, err := QueryRecords(context.Background(), g, "example.com.", RecordFilter{Name: "_acme-challenge.example.com.", Type: "TXT"})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if expect := g.recs[:2]; !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Expected %+v but got %+v", expect, actual)
+	}
+
+	q := &queryingGetter{}
+	filter := RecordFilter{Name: "www", Type: "A"}
+	if _, err := QueryRecords(context.Background(), q, "example.com.", filter); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if q.calls != 0 {
+		t.Errorf("Expected GetRecords not to be called but it was called %d times", q.calls)
+	}
+	if q.filter != filter {
+		t.Errorf("Expected GetRecordsMatching to be called with %+v but got %+v", filter, q.filter)
+	}
+}
@@ -45,6 +45,12 @@ import (
 type RecordGetter interface {
 	// GetRecords returns all the records in the DNS zone.
 	//
+	// Records managed by the provider itself, such as DNSKEY and CDNSKEY
+	// records in signed zones, are only returned if the provider exposes
+	// them through its API.
+	//
+	// If the zone does not exist, the error should wrap ErrZoneNotFound.
+	//
 	// Implementations must honor context cancellation and be safe for
 	// concurrent use.
 	GetRecords(ctx context.Context, zone string) ([]Record, error)
@@ -54,7 +60,11 @@ type RecordGetter interface {
 type RecordAppender interface {
 	// AppendRecords creates the requested records in the given zone
 	// and returns the populated records that were created. It never
-	// changes existing records.
+	// changes existing records. If a record cannot be created because
+	// it already exists, the error should wrap ErrRecordExists.
+	//
+	// The returned records should be in the same order as the input;
+	// providers that cannot ensure this may use ReorderToInput.
 	//
 	// Implementations must honor context cancellation and be safe for
 	// concurrent use.
@@ -94,16 +104,154 @@ type RecordDeleter interface {
 	DeleteRecords(ctx context.Context, zone string, recs []Record) ([]Record, error)
 }
 
+// RecordQuerier can get only the records in a DNS zone that match a
+// filter. It is optional; it allows providers whose APIs support
+// server-side filtering to avoid retrieving an entire zone, for example
+// when only the ACME challenge records are needed. Callers can use
+// QueryRecords to take advantage of it when it is available.
+type RecordQuerier interface {
+	// GetRecordsMatching returns the records in the DNS zone that match
+	// filter. An empty filter matches all records, making this
+	// equivalent to GetRecords.
+	//
+	// Implementations must honor context cancellation and be safe for
+	// concurrent use.
+	GetRecordsMatching(ctx context.Context, zone string, filter RecordFilter) ([]Record, error)
+}
+
+// RecordPager can get the records in a DNS zone one page at a time.
+// It is optional; providers whose APIs paginate large zones may
+// implement it so that callers need not hold an entire zone in memory
+// or make one very expensive call. Callers can fall back to GetRecords
+// for providers that do not implement it, or use CollectAllPages to get
+// all the records from a pager.
+type RecordPager interface {
+	// GetRecordsPage returns a page of records in the DNS zone and the
+	// token of the next page. An empty pageToken requests the first
+	// page, and an empty nextToken is returned with the last page. Page
+	// tokens are opaque to callers and only valid for the same provider
+	// and zone; the size of pages is chosen by the provider.
+	//
+	// The records in a page are in no particular order, and pages may be
+	// inconsistent with each other if the zone changes between calls.
+	//
+	// Implementations must honor context cancellation and be safe for
+	// concurrent use.
+	GetRecordsPage(ctx context.Context, zone string, pageToken string) (records []Record, nextToken string, err error)
+}
+
+// RecordCounter can count the records in a DNS zone without getting
+// them. It is optional; providers whose APIs report zone sizes may
+// implement it to let callers size up a zone cheaply, for example
+// before deciding whether to page through it. Callers can use
+// CountRecords to fall back to GetRecords otherwise.
+type RecordCounter interface {
+	// CountRecords returns the number of records in the DNS zone.
+	//
+	// Implementations must honor context cancellation and be safe for
+	// concurrent use.
+	CountRecords(ctx context.Context, zone string) (int, error)
+}
+
+// RecordWatcher can notify of changes to the records in a DNS zone. It
+// is optional; providers that support webhooks, long-polling, or zone
+// transfers with NOTIFY may implement it to spare callers from polling.
+type RecordWatcher interface {
+	// WatchRecords returns a channel on which the zone's current records
+	// are sent, once initially and then whenever they change. Each value
+	// is the complete set of records in the zone, not only those that
+	// changed.
+	//
+	// Delivery is at-least-once: a set of records may be sent again even
+	// if it has not changed. Changes may also be coalesced, so a receiver
+	// that falls behind gets the latest records rather than every
+	// intermediate state. The channel is closed when ctx is canceled or
+	// the watch otherwise ends.
+	//
+	// Implementations must be safe for concurrent use.
+	WatchRecords(ctx context.Context, zone string) (<-chan []Record, error)
+}
+
+// RecordModifier can update individual existing records in a DNS zone.
+type RecordModifier interface {
+	// ModifyRecords updates existing records in the zone in place and
+	// returns the records as they were updated. Unlike SetRecords, it
+	// operates on individual records rather than RRsets: other records
+	// in the same RRset are not affected.
+	//
+	// Each input record is identified by its provider-specific ID, which
+	// is required; it is typically obtained from a previous call to
+	// GetRecords. All other fields are the new values for the record.
+	// It is an error if a record with the given ID does not exist; the
+	// error should wrap ErrRecordNotFound.
+	//
+	// Implementations must honor context cancellation and be safe for
+	// concurrent use.
+	ModifyRecords(ctx context.Context, zone string, recs []Record) ([]Record, error)
+}
+
 // ZoneLister can list available DNS zones.
 type ZoneLister interface {
 	// ListZones returns the list of available DNS zones for use by
-	// other libdns methods.
+	// other libdns methods. Fields of the returned zones other than the
+	// Name may be zero if the provider does not report them.
 	//
 	// Implementations must honor context cancellation and be safe for
 	// concurrent use.
 	ListZones(ctx context.Context) ([]Zone, error)
 }
 
+// ZoneCreator can create DNS zones.
+type ZoneCreator interface {
+	// CreateZone creates the given zone and returns it as created, with
+	// any metadata the provider reports. Only the zone's Name is
+	// required; providers may ignore other fields they do not support.
+	// Providers typically populate a new zone with default SOA and NS
+	// records.
+	//
+	// It is an error if the zone already exists; CreateZone never
+	// modifies an existing zone. Callers that want idempotent behavior
+	// can check for the zone with ListZones first.
+	//
+	// Implementations must honor context cancellation and be safe for
+	// concurrent use.
+	CreateZone(ctx context.Context, zone Zone) (Zone, error)
+}
+
+// ZoneDeleter can delete DNS zones.
+type ZoneDeleter interface {
+	// DeleteZone deletes the zone and all of its records. Deleting a
+	// zone that does not exist is not an error, so it is safe to retry.
+	//
+	// Implementations must honor context cancellation and be safe for
+	// concurrent use.
+	DeleteZone(ctx context.Context, zone string) error
+}
+
+// RateLimitReporter is implemented by providers that track the rate
+// limits of their APIs, for example from rate-limit or Retry-After
+// headers in responses, so that callers making many changes can pace
+// themselves before they are throttled.
+type RateLimitReporter interface {
+	// RateLimit returns the number of requests remaining in the current
+	// rate-limit window and the time at which the window resets, as of
+	// the most recent response from the provider. It returns ok=false
+	// if the provider has no rate-limit information yet.
+	//
+	// Implementations must be safe for concurrent use.
+	RateLimit() (remaining int, resetAt time.Time, ok bool)
+}
+
+// NameFormatter is implemented by providers to declare the format of
+// the record names they return. Providers that do not implement it are
+// assumed to follow the libdns convention of relative names; see
+// ProviderNameFormat.
+type NameFormatter interface {
+	// NameFormat returns the format of the names of records returned
+	// by the provider.
+	NameFormat() NameFormat
+}
+
 // Record is a generalized representation of a DNS record.
 //
 // The values of this struct should be free of zone-file-specific syntax,
@@ -127,8 +275,17 @@ type Record struct {
 }
 
 // Zone is a generalized representation of a DNS zone.
+//
+// Only the Name is required. The other fields are metadata filled in on
+// a best-effort basis by providers that report it; they are zero values
+// if the provider does not.
 type Zone struct {
 	Name string
+
+	// zone metadata, if reported by the provider
+	Serial uint32 // serial number of the zone's SOA record
+	DNSSEC bool   // whether the zone is signed
+	Kind   string // e.g. "primary" or "secondary"
 }
 
 // ToSRV parses the record into a SRV struct with fully-parsed, literal values.
@@ -197,6 +354,10 @@ func (s SRV) ToRecord() Record {
 // RelativeName makes fqdn relative to zone. For example, for a FQDN of
 // "sub.example.com" and a zone of "example.com", it outputs "sub".
 //
+// Labels are compared case-insensitively, since DNS names are, and
+// internationalized labels match their Punycode equivalents; the
+// returned name keeps the case and form of fqdn.
+//
 // If fqdn cannot be expressed relative to zone, the input fqdn is returned.
 func RelativeName(fqdn, zone string) string {
 	// liberally ignore trailing dots on both fqdn and zone, because
@@ -205,12 +366,67 @@ func RelativeName(fqdn, zone string) string {
 	// (initially implemented because Cloudflare returns "fully-
 	// qualified" domains in their records without a trailing dot,
 	// but the input zone typically has a trailing dot)
-	return strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(fqdn, "."), strings.TrimSuffix(zone, ".")), ".")
+	name, _ := relativeName(strings.TrimSuffix(fqdn, "."), strings.TrimSuffix(zone, "."))
+	return name
+}
+
+// RelativeNameStrict is like RelativeName, but also reports whether
+// fqdn is in zone. If it is, the name relative to zone is returned,
+// with "@" for the zone apex, and true. Otherwise, fqdn is returned
+// unchanged, including any trailing dot, and false, so callers can tell
+// a name that cannot be expressed relative to zone from one that was
+// already relative. All names are in the root zone, "." (or "").
+func RelativeNameStrict(fqdn, zone string) (string, bool) {
+	name, ok := relativeName(strings.TrimSuffix(fqdn, "."), strings.TrimSuffix(zone, "."))
+	if !ok {
+		return fqdn, false
+	}
+	if name == "" {
+		name = "@"
+	}
+	return name, true
+}
+
+// relativeName makes fqdn relative to zone, neither of which may have
+// a trailing dot, and reports whether fqdn is in zone. If it is not,
+// fqdn is returned.
+func relativeName(fqdn, zone string) (string, bool) {
+	if zone == "" {
+		return fqdn, true
+	}
+
+	fqdnLabels := strings.Split(fqdn, ".")
+	zoneLabels := strings.Split(zone, ".")
+	if len(fqdnLabels) < len(zoneLabels) {
+		return fqdn, false
+	}
+	offset := len(fqdnLabels) - len(zoneLabels)
+	for i, zoneLabel := range zoneLabels {
+		if !labelsEqual(fqdnLabels[offset+i], zoneLabel) {
+			return fqdn, false
+		}
+	}
+	return strings.Join(fqdnLabels[:offset], "."), true
+}
+
+// labelsEqual reports whether two DNS labels are equivalent, ignoring
+// case and comparing internationalized labels in their ASCII form.
+func labelsEqual(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	if isASCII(a) && isASCII(b) {
+		return false
+	}
+	asciiA, errA := ToPunycode(a)
+	asciiB, errB := ToPunycode(b)
+	return errA == nil && errB == nil && strings.EqualFold(asciiA, asciiB)
 }
 
 // AbsoluteName makes name into a fully-qualified domain name (FQDN) by
 // prepending it to zone and tidying up the dots. For example, an input
 // of name "sub" and zone "example.com." will return "sub.example.com.".
+// The case and form (Unicode or Punycode) of both inputs are preserved.
 func AbsoluteName(name, zone string) string {
 	if zone == "" {
 		return strings.Trim(name, ".")
@@ -1,8 +1,10 @@
 package libdns
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 )
 
 func ExampleRelativeName() {
@@ -70,6 +72,71 @@ func TestRelativeName(t *testing.T) {
 			zone:   "example.net",
 			expect: "example.com",
 		},
+		{
+			fqdn:   "example.com",
+			zone:   "example.com.",
+			expect: "",
+		},
+		{
+			fqdn:   "fooexample.com",
+			zone:   "example.com",
+			expect: "fooexample.com",
+		},
+		{
+			fqdn:   "Sub.Example.COM",
+			zone:   "example.com.",
+			expect: "Sub",
+		},
+		{
+			fqdn:   "sub.example.com.",
+			zone:   "EXAMPLE.com",
+			expect: "sub",
+		},
+		{
+			fqdn:   "Sub.Example.COM.",
+			zone:   "example.com.",
+			expect: "Sub",
+		},
+		{
+			fqdn:   "Foo.Bar.EXAMPLE.com.",
+			zone:   "Example.Com.",
+			expect: "Foo.Bar",
+		},
+		{
+			fqdn:   "www.müller.de.",
+			zone:   "müller.de.",
+			expect: "www",
+		},
+		{
+			fqdn:   "www.müller.de.",
+			zone:   "xn--mller-kva.de.",
+			expect: "www",
+		},
+		{
+			fqdn:   "Straße.xn--mller-kva.de",
+			zone:   "Müller.de.",
+			expect: "Straße",
+		},
+		{
+			fqdn:   "www.müller.de.",
+			zone:   "mueller.de.",
+			expect: "www.müller.de",
+		},
+		{
+			fqdn:   "*.example.com.",
+			zone:   "example.com.",
+			expect: "*",
+		},
+		{
+			fqdn:   "*.sub.example.com.",
+			zone:   "example.com.",
+			expect: "*.sub",
+		},
+		{
+			fqdn:   "*.sub.example.com.",
+			zone:   "sub.example.com.",
+			expect: "*",
+		},
 	} {
 		actual := RelativeName(test.fqdn, test.zone)
 		if actual != test.expect {
@@ -79,6 +146,69 @@ func TestRelativeName(t *testing.T) {
 	}
 }
 
+func TestRelativeNameStrict(t *testing.T) {
+	for i, test := range []struct {
+		fqdn, zone string
+		expect     string
+		inZone     bool
+	}{
+		{
+			fqdn:   "sub.example.com.",
+			zone:   "example.com.",
+			expect: "sub",
+			inZone: true,
+		},
+		{
+			fqdn:   "foo.bar.Example.com",
+			zone:   "example.com.",
+			expect: "foo.bar",
+			inZone: true,
+		},
+		{
+			fqdn:   "example.com.",
+			zone:   "example.com.",
+			expect: "@",
+			inZone: true,
+		},
+		{
+			fqdn:   "example.com",
+			zone:   "example.com.",
+			expect: "@",
+			inZone: true,
+		},
+		{
+			fqdn:   "sub.example.net.",
+			zone:   "example.com.",
+			expect: "sub.example.net.",
+			inZone: false,
+		},
+		{
+			fqdn:   "sub.example.net",
+			zone:   "example.com.",
+			expect: "sub.example.net",
+			inZone: false,
+		},
+		{
+			fqdn:   "com.",
+			zone:   "example.com.",
+			expect: "com.",
+			inZone: false,
+		},
+		{
+			fqdn:   "sub.example.com.",
+			zone:   ".",
+			expect: "sub.example.com",
+			inZone: true,
+		},
+	} {
+		actual, inZone := RelativeNameStrict(test.fqdn, test.zone)
+		if actual != test.expect || inZone != test.inZone {
+			t.Errorf("Test %d: FQDN=%s ZONE=%s - expected (%q, %t) but got (%q, %t)",
+				i, test.fqdn, test.zone, test.expect, test.inZone, actual, inZone)
+		}
+	}
+}
+
 func TestAbsoluteName(t *testing.T) {
 	for i, test := range []struct {
 		name, zone string
@@ -124,6 +254,31 @@ func TestAbsoluteName(t *testing.T) {
 			zone:   "",
 			expect: "foo",
 		},
+		{
+			name:   "WWW",
+			zone:   "Example.COM.",
+			expect: "WWW.Example.COM.",
+		},
+		{
+			name:   "Sub",
+			zone:   "example.com.",
+			expect: "Sub.example.com.",
+		},
+		{
+			name:   "www",
+			zone:   "müller.de.",
+			expect: "www.müller.de.",
+		},
+		{
+			name:   "*",
+			zone:   "example.com.",
+			expect: "*.example.com.",
+		},
+		{
+			name:   "*.sub",
+			zone:   "example.com.",
+			expect: "*.sub.example.com.",
+		},
 	} {
 		actual := AbsoluteName(test.name, test.zone)
 		if actual != test.expect {
@@ -194,3 +349,58 @@ func TestSRVRecords(t *testing.T) {
 		}
 	}
 }
+
+// mockWatcher is a RecordWatcher that emits the records sent on its
+// updates channel.
+type mockWatcher struct {
+	initial []Record
+	updates chan []Record
+}
+
+func (w mockWatcher) WatchRecords(ctx context.Context, zone string) (<-chan []Record, error) {
+	ch := make(chan []Record)
+	go func() {
+		defer close(ch)
+		recs := w.initial
+		for {
+			select {
+			case ch <- recs:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case recs = <-w.updates:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func TestRecordWatcherClosesOnCancel(t *testing.T) {
+	var w RecordWatcher = mockWatcher{
+		initial: []Record{{Type: "A", Name: "www", Value: "192.0.2.1"}},
+		updates: make(chan []Record),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := w.WatchRecords(ctx, "example.com.")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if recs := <-ch; len(recs) != 1 || recs[0].Value != "192.0.2.1" {
+		t.Errorf("Expected initial records but got %+v", recs)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Errorf("Expected channel to be closed after cancellation")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for channel to close")
+	}
+}
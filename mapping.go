@@ -0,0 +1,112 @@
+package libdns
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// MapToRecord converts src, a provider's native representation of a
+// record, to a Record by reading fields of src tagged with `libdns:"..."`
+// struct tags. src must be a struct or a pointer to one. The supported
+// tags are:
+//
+//   - id: the provider-specific ID
+//   - type: the record type
+//   - name: the record name, which is made relative to zone
+//   - value: the record value
+//   - ttl: the TTL; numbers are seconds, strings are parsed with ParseTTL
+//   - priority, weight: the type-dependent numeric fields
+//
+// Tagged fields may be strings or integers (or a time.Duration for ttl);
+// numbers are converted to and from strings as needed. Untagged fields
+// are ignored.
+func MapToRecord(src any, zone string) (Record, error) {
+	v := reflect.ValueOf(src)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return Record{}, fmt.Errorf("cannot map nil %T", src)
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return Record{}, fmt.Errorf("cannot map %T: not a struct", src)
+	}
+
+	var rec Record
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := t.Field(i).Tag.Lookup("libdns")
+		if !ok {
+			continue
+		}
+		field := v.Field(i)
+
+		var err error
+		switch tag {
+		case "id":
+			rec.ID, err = mappedString(field)
+		case "type":
+			rec.Type, err = mappedString(field)
+		case "name":
+			var name string
+			name, err = mappedString(field)
+			rec.Name = RelativeName(name, zone)
+		case "value":
+			rec.Value, err = mappedString(field)
+		case "ttl":
+			rec.TTL, err = mappedTTL(field)
+		case "priority":
+			rec.Priority, err = mappedUint(field)
+		case "weight":
+			rec.Weight, err = mappedUint(field)
+		default:
+			err = fmt.Errorf("unknown tag")
+		}
+		if err != nil {
+			return Record{}, fmt.Errorf("field %s (libdns:%q): %v", t.Field(i).Name, tag, err)
+		}
+	}
+
+	return rec, nil
+}
+
+func mappedString(v reflect.Value) (string, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func mappedUint(v reflect.Value) (uint, error) {
+	switch v.Kind() {
+	case reflect.String:
+		n, err := strconv.ParseUint(v.String(), 10, 0)
+		return uint(n), err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() < 0 {
+			return 0, fmt.Errorf("negative value %d", v.Int())
+		}
+		return uint(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uint(v.Uint()), nil
+	}
+	return 0, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func mappedTTL(v reflect.Value) (time.Duration, error) {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		return time.Duration(v.Int()), nil
+	}
+	if v.Kind() == reflect.String {
+		return ParseTTL(v.String())
+	}
+	secs, err := mappedUint(v)
+	return time.Duration(secs) * time.Second, err
+}
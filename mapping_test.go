@@ -0,0 +1,97 @@
+package libdns
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMapToRecord(t *testing.T) {
+	type providerRecord struct {
+		ID       int    `libdns:"id"`
+		Kind     string `libdns:"type"`
+		Host     string `libdns:"name"`
+		Content  string `libdns:"value"`
+		TTL      int    `libdns:"ttl"`
+		Priority uint16 `libdns:"priority"`
+		Comment  string
+	}
+	type stringyRecord struct {
+		Type string `libdns:"type"`
+		Name string `libdns:"name"`
+		Data string `libdns:"value"`
+		TTL  string `libdns:"ttl"`
+		Prio string `libdns:"priority"`
+	}
+
+	for i, test := range []struct {
+		src    any
+		expect Record
+	}{
+		{
+			src: providerRecord{
+				ID:      42,
+				Kind:    "A",
+				Host:    "www.example.com.",
+				Content: "192.0.2.1",
+				TTL:     300,
+				Comment: "ignored",
+			},
+			expect: Record{ID: "42", Type: "A", Name: "www", Value: "192.0.2.1", TTL: 5 * time.Minute},
+		},
+		{
+			src: &providerRecord{
+				ID:       43,
+				Kind:     "MX",
+				Host:     "example.com",
+				Content:  "mail.example.com.",
+				TTL:      3600,
+				Priority: 10,
+			},
+			expect: Record{ID: "43", Type: "MX", Name: "", Value: "mail.example.com.", TTL: time.Hour, Priority: 10},
+		},
+		{
+			src: stringyRecord{
+				Type: "MX",
+				Name: "sub",
+				Data: "mail.example.com.",
+				TTL:  "1h",
+				Prio: "20",
+			},
+			expect: Record{Type: "MX", Name: "sub", Value: "mail.example.com.", TTL: time.Hour, Priority: 20},
+		},
+	} {
+		actual, err := MapToRecord(test.src, "example.com.")
+		if err != nil {
+			t.Errorf("Test %d: expected no error but got: %v", i, err)
+			continue
+		}
+		if actual != test.expect {
+			t.Errorf("Test %d: expected %+v\nbut got  %+v", i, test.expect, actual)
+		}
+	}
+}
+
+func TestMapToRecordErrors(t *testing.T) {
+	type badTag struct {
+		X string `libdns:"bogus"`
+	}
+	type badType struct {
+		X []string `libdns:"value"`
+	}
+	type badPriority struct {
+		X int `libdns:"priority"`
+	}
+	var nilPtr *badTag
+
+	for i, src := range []any{
+		"not a struct",
+		nilPtr,
+		badTag{},
+		badType{},
+		badPriority{X: -1},
+	} {
+		if _, err := MapToRecord(src, "example.com."); err == nil {
+			t.Errorf("Test %d: expected error for %#v but got none", i, src)
+		}
+	}
+}
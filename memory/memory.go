@@ -0,0 +1,290 @@
+// Package memory implements an in-memory libdns provider. It is a
+// reference implementation of the libdns interfaces and is useful for
+// testing code that uses libdns without a real DNS provider.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/libdns/libdns"
+)
+
+// Provider stores DNS zones and their records in memory. The zero value
+// is ready to use and has no zones. Zones are created when records are
+// first added to them with AppendRecords or SetRecords.
+//
+// Record names are stored relative to the zone, with "@" for the apex.
+// Each record is assigned an ID when it is added.
+type Provider struct {
+	mu     sync.Mutex
+	zones  map[string]*zone
+	lastID int
+}
+
+type zone struct {
+	name    string // as first given
+	records []libdns.Record
+}
+
+// GetRecords returns all the records in the zone.
+func (p *Provider) GetRecords(ctx context.Context, zoneName string) ([]libdns.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	z, ok := p.zones[zoneKey(zoneName)]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", zoneName, libdns.ErrZoneNotFound)
+	}
+	return append([]libdns.Record(nil), z.records...), nil
+}
+
+// AppendRecords adds the records to the zone, creating the zone if
+// necessary. It is an error if any of the records already exist, in
+// which case no records are added.
+func (p *Provider) AppendRecords(ctx context.Context, zoneName string, recs []libdns.Record) ([]libdns.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	z := p.zone(zoneName)
+
+	added := make([]libdns.Record, 0, len(recs))
+	for _, rec := range recs {
+		rec = normalize(rec, zoneName)
+		if containsRecord(z.records, rec) || containsRecord(added, rec) {
+			return nil, fmt.Errorf("%s %s %q: %w", rec.Name, rec.Type, rec.Value, libdns.ErrRecordExists)
+		}
+		rec.ID = p.nextID()
+		added = append(added, rec)
+	}
+	z.records = append(z.records, added...)
+	return added, nil
+}
+
+// SetRecords replaces the RRsets (records with the same name and type)
+// in the zone which are present in the input with the input records,
+// creating the zone if necessary. Records in the input with the ID of
+// an existing record replace that record; it is an error if there is
+// no record with that ID, or if the input has the same ID or the same
+// record more than once, in which case the zone is not changed. Other
+// records are not affected.
+func (p *Provider) SetRecords(ctx context.Context, zoneName string, recs []libdns.Record) ([]libdns.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var existingRecs []libdns.Record
+	if z, ok := p.zones[zoneKey(zoneName)]; ok {
+		existingRecs = z.records
+	}
+
+	// IDs that belong to an input record, each of which may be used once
+	usedIDs := make(map[string]bool)
+	set := make([]libdns.Record, len(recs))
+	for i, rec := range recs {
+		rec = normalize(rec, zoneName)
+		if containsRecord(set[:i], rec) {
+			return nil, fmt.Errorf("%s %s %q given more than once: %w", rec.Name, rec.Type, rec.Value, libdns.ErrRecordExists)
+		}
+		if rec.ID != "" {
+			if !containsID(existingRecs, rec.ID) {
+				return nil, fmt.Errorf("record ID %s: %w", rec.ID, libdns.ErrRecordNotFound)
+			}
+			if usedIDs[rec.ID] {
+				return nil, fmt.Errorf("record ID %s given more than once", rec.ID)
+			}
+			usedIDs[rec.ID] = true
+		}
+		set[i] = rec
+	}
+
+	z := p.zone(zoneName)
+	var kept []libdns.Record
+	for _, existing := range z.records {
+		replaced := false
+		for i, rec := range set {
+			if rec.ID == existing.ID || sameRRSet(existing, rec) {
+				replaced = true
+				// keep the ID of an identical record being replaced
+				if rec.ID == "" && !usedIDs[existing.ID] && sameRecord(existing, rec) {
+					set[i].ID = existing.ID
+					usedIDs[existing.ID] = true
+				}
+			}
+		}
+		if !replaced {
+			kept = append(kept, existing)
+		}
+	}
+	for i := range set {
+		if set[i].ID == "" {
+			set[i].ID = p.nextID()
+		}
+	}
+	z.records = append(kept, set...)
+	return set, nil
+}
+
+// DeleteRecords deletes the records from the zone which match the input
+// records, returning the records that were deleted. An input record
+// with an ID matches only the record with that ID. Otherwise, it
+// matches records with its name, and its type, TTL, priority, weight
+// and value if they are not empty.
+func (p *Provider) DeleteRecords(ctx context.Context, zoneName string, recs []libdns.Record) ([]libdns.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	z, ok := p.zones[zoneKey(zoneName)]
+	if !ok {
+		return nil, nil
+	}
+
+	var kept, deleted []libdns.Record
+	for _, existing := range z.records {
+		matched := false
+		for _, rec := range recs {
+			if deleteMatches(normalize(rec, zoneName), existing) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			deleted = append(deleted, existing)
+		} else {
+			kept = append(kept, existing)
+		}
+	}
+	z.records = kept
+	return deleted, nil
+}
+
+// ListZones returns the zones in the provider, sorted by name.
+func (p *Provider) ListZones(ctx context.Context) ([]libdns.Zone, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	zones := make([]libdns.Zone, 0, len(p.zones))
+	for _, z := range p.zones {
+		zones = append(zones, libdns.Zone{Name: z.name})
+	}
+	sort.Slice(zones, func(i, j int) bool { return zones[i].Name < zones[j].Name })
+	return zones, nil
+}
+
+// zone returns the zone with the given name, creating it if necessary.
+// p.mu must be locked.
+func (p *Provider) zone(name string) *zone {
+	if p.zones == nil {
+		p.zones = make(map[string]*zone)
+	}
+	key := zoneKey(name)
+	z, ok := p.zones[key]
+	if !ok {
+		z = &zone{name: name}
+		p.zones[key] = z
+	}
+	return z
+}
+
+// nextID returns a new record ID. p.mu must be locked.
+func (p *Provider) nextID() string {
+	p.lastID++
+	return strconv.Itoa(p.lastID)
+}
+
+func zoneKey(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, ".")) + "."
+}
+
+// normalize makes the name of rec relative to zone, with "@" for the
+// apex, and upper-cases its type.
+func normalize(rec libdns.Record, zone string) libdns.Record {
+	if strings.HasSuffix(rec.Name, ".") {
+		rec.Name = libdns.RelativeName(rec.Name, zone)
+	}
+	if rec.Name == "" {
+		rec.Name = "@"
+	}
+	rec.Type = strings.ToUpper(rec.Type)
+	return rec
+}
+
+func sameRRSet(a, b libdns.Record) bool {
+	return strings.EqualFold(a.Name, b.Name) && a.Type == b.Type
+}
+
+func sameRecord(a, b libdns.Record) bool {
+	a.ID, b.ID = "", ""
+	a.TTL, b.TTL = 0, 0
+	return libdns.RecordsEqual(a, b, "")
+}
+
+func containsRecord(recs []libdns.Record, rec libdns.Record) bool {
+	for _, r := range recs {
+		if sameRecord(r, rec) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsID(recs []libdns.Record, id string) bool {
+	for _, r := range recs {
+		if r.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func deleteMatches(rec, existing libdns.Record) bool {
+	if rec.ID != "" {
+		return rec.ID == existing.ID
+	}
+	if !strings.EqualFold(rec.Name, existing.Name) {
+		return false
+	}
+	if rec.Type != "" && rec.Type != existing.Type {
+		return false
+	}
+	if rec.TTL != 0 && rec.TTL != existing.TTL {
+		return false
+	}
+	if rec.Value != "" {
+		// fields not given are not compared
+		if rec.Type == "" {
+			rec.Type = existing.Type
+		}
+		if rec.Priority == 0 {
+			rec.Priority = existing.Priority
+		}
+		if rec.Weight == 0 {
+			rec.Weight = existing.Weight
+		}
+		return sameRecord(rec, existing)
+	}
+	return true
+}
+
+// Interface guards
+var (
+	_ libdns.RecordGetter   = (*Provider)(nil)
+	_ libdns.RecordAppender = (*Provider)(nil)
+	_ libdns.RecordSetter   = (*Provider)(nil)
+	_ libdns.RecordDeleter  = (*Provider)(nil)
+	_ libdns.ZoneLister     = (*Provider)(nil)
+)
@@ -0,0 +1,388 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+const testZone = "example.com."
+
+// stripIDs returns recs without their IDs, for comparison.
+func stripIDs(recs []libdns.Record) []libdns.Record {
+	out := make([]libdns.Record, len(recs))
+	for i, rec := range recs {
+		rec.ID = ""
+		out[i] = rec
+	}
+	return out
+}
+
+func equalRecords(a, b []libdns.Record) bool {
+	a, b = stripIDs(a), stripIDs(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGetRecordsUnknownZone(t *testing.T) {
+	var p Provider
+	_, err := p.GetRecords(context.Background(), testZone)
+	if !errors.Is(err, libdns.ErrZoneNotFound) {
+		t.Errorf("Expected ErrZoneNotFound but got: %v", err)
+	}
+}
+
+func TestAppendRecords(t *testing.T) {
+	ctx := context.Background()
+	var p Provider
+
+	input := []libdns.Record{
+		{Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "a", Name: "www.example.com.", Value: "192.0.2.2", TTL: time.Hour},
+		{Type: "TXT", Name: "", Value: "hello", TTL: time.Hour},
+	}
+	expect := []libdns.Record{
+		{Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "A", Name: "www", Value: "192.0.2.2", TTL: time.Hour},
+		{Type: "TXT", Name: "@", Value: "hello", TTL: time.Hour},
+	}
+
+	added, err := p.AppendRecords(ctx, testZone, input)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !equalRecords(added, expect) {
+		t.Errorf("Expected added records %+v but got %+v", expect, added)
+	}
+	for i, rec := range added {
+		if rec.ID == "" {
+			t.Errorf("Record %d: expected an ID to be assigned", i)
+		}
+	}
+
+	got, err := p.GetRecords(ctx, testZone)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !equalRecords(got, expect) {
+		t.Errorf("Expected records %+v but got %+v", expect, got)
+	}
+
+	_, err = p.AppendRecords(ctx, testZone, []libdns.Record{
+		{Type: "A", Name: "new", Value: "192.0.2.3"},
+		{Type: "A", Name: "WWW", Value: "192.0.2.1"},
+	})
+	if !errors.Is(err, libdns.ErrRecordExists) {
+		t.Errorf("Expected ErrRecordExists but got: %v", err)
+	}
+	got, _ = p.GetRecords(ctx, testZone)
+	if !equalRecords(got, expect) {
+		t.Errorf("Expected records to be unchanged after failed append but got %+v", got)
+	}
+}
+
+func TestSetRecords(t *testing.T) {
+	ctx := context.Background()
+	var p Provider
+
+	initial, err := p.AppendRecords(ctx, testZone, []libdns.Record{
+		{Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "A", Name: "www", Value: "192.0.2.2", TTL: time.Hour},
+		{Type: "AAAA", Name: "www", Value: "2001:db8::1", TTL: time.Hour},
+		{Type: "TXT", Name: "@", Value: "hello", TTL: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	// replace the www A RRset; the AAAA and TXT RRsets are not affected
+	set, err := p.SetRecords(ctx, testZone, []libdns.Record{
+		{Type: "A", Name: "www", Value: "192.0.2.2", TTL: time.Minute},
+		{Type: "A", Name: "www", Value: "192.0.2.3", TTL: time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if set[0].ID != initial[1].ID {
+		t.Errorf("Expected unchanged record to keep ID %s but got %s", initial[1].ID, set[0].ID)
+	}
+
+	expect := []libdns.Record{
+		{Type: "AAAA", Name: "www", Value: "2001:db8::1", TTL: time.Hour},
+		{Type: "TXT", Name: "@", Value: "hello", TTL: time.Hour},
+		{Type: "A", Name: "www", Value: "192.0.2.2", TTL: time.Minute},
+		{Type: "A", Name: "www", Value: "192.0.2.3", TTL: time.Minute},
+	}
+	got, _ := p.GetRecords(ctx, testZone)
+	if !equalRecords(got, expect) {
+		t.Errorf("Expected records %+v but got %+v", expect, got)
+	}
+
+	// replace a record by ID
+	_, err = p.SetRecords(ctx, testZone, []libdns.Record{
+		{ID: initial[3].ID, Type: "TXT", Name: "@", Value: "goodbye", TTL: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	got, _ = p.GetRecords(ctx, testZone)
+	expect[1].Value = "goodbye"
+	expect = append(expect[:1], append(expect[2:], expect[1])...)
+	if !equalRecords(got, expect) {
+		t.Errorf("Expected records %+v but got %+v", expect, got)
+	}
+}
+
+func TestSetRecordsDuplicateInput(t *testing.T) {
+	ctx := context.Background()
+	var p Provider
+
+	initial, err := p.AppendRecords(ctx, testZone, []libdns.Record{
+		{Type: "A", Name: "x", Value: "192.0.2.1"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	_, err = p.SetRecords(ctx, testZone, []libdns.Record{
+		{Type: "A", Name: "x", Value: "192.0.2.1"},
+		{Type: "A", Name: "x", Value: "192.0.2.1"},
+	})
+	if !errors.Is(err, libdns.ErrRecordExists) {
+		t.Errorf("Expected ErrRecordExists but got: %v", err)
+	}
+	if got, _ := p.GetRecords(ctx, testZone); !reflect.DeepEqual(got, initial) {
+		t.Errorf("Expected zone to be unchanged %+v but got %+v", initial, got)
+	}
+
+	// an existing ID is given to at most one record
+	set, err := p.SetRecords(ctx, testZone, []libdns.Record{
+		{ID: initial[0].ID, Type: "A", Name: "x", Value: "192.0.2.2"},
+		{Type: "A", Name: "x", Value: "192.0.2.1"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if set[0].ID != initial[0].ID || set[1].ID == initial[0].ID {
+		t.Errorf("Expected only the first record to have ID %s but got %+v", initial[0].ID, set)
+	}
+
+	_, err = p.SetRecords(ctx, testZone, []libdns.Record{
+		{ID: set[0].ID, Type: "A", Name: "x", Value: "192.0.2.3"},
+		{ID: set[0].ID, Type: "A", Name: "x", Value: "192.0.2.4"},
+	})
+	if err == nil {
+		t.Errorf("Expected error for repeated ID but got none")
+	}
+}
+
+func TestSetRecordsUnknownID(t *testing.T) {
+	ctx := context.Background()
+	var p Provider
+
+	_, err := p.SetRecords(ctx, testZone, []libdns.Record{
+		{ID: "2", Type: "TXT", Name: "y", Value: "hello"},
+	})
+	if !errors.Is(err, libdns.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound but got: %v", err)
+	}
+
+	added, err := p.AppendRecords(ctx, testZone, []libdns.Record{
+		{Type: "A", Name: "a", Value: "192.0.2.1"},
+		{Type: "A", Name: "b", Value: "192.0.2.2"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	deleted, err := p.DeleteRecords(ctx, testZone, []libdns.Record{{ID: added[1].ID}})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != added[1] {
+		t.Errorf("Expected only %+v to be deleted but got %+v", added[1], deleted)
+	}
+}
+
+func TestDeleteRecords(t *testing.T) {
+	ctx := context.Background()
+	var p Provider
+
+	initial, err := p.AppendRecords(ctx, testZone, []libdns.Record{
+		{Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "A", Name: "www", Value: "192.0.2.2", TTL: time.Hour},
+		{Type: "TXT", Name: "www", Value: "hello", TTL: time.Hour},
+		{Type: "TXT", Name: "_acme-challenge", Value: "token1", TTL: time.Minute},
+		{Type: "TXT", Name: "_acme-challenge", Value: "token2", TTL: time.Minute},
+		{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10, TTL: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	for i, test := range []struct {
+		input  []libdns.Record
+		expect []libdns.Record
+	}{
+		{
+			input:  []libdns.Record{{ID: initial[0].ID}},
+			expect: initial[0:1],
+		},
+		{
+			input:  []libdns.Record{{Type: "TXT", Name: "_acme-challenge", Value: "token2"}},
+			expect: initial[4:5],
+		},
+		{
+			input:  []libdns.Record{{Type: "A", Name: "nothing"}},
+			expect: nil,
+		},
+		{
+			input:  []libdns.Record{{Name: "www.example.com."}},
+			expect: initial[1:3],
+		},
+		{
+			input:  []libdns.Record{{Type: "MX", Name: "", Value: "Mail.Example.com."}},
+			expect: initial[5:6],
+		},
+	} {
+		deleted, err := p.DeleteRecords(ctx, testZone, test.input)
+		if err != nil {
+			t.Errorf("Test %d: expected no error but got: %v", i, err)
+			continue
+		}
+		if !equalRecords(deleted, test.expect) {
+			t.Errorf("Test %d: expected deleted records %+v but got %+v", i, test.expect, deleted)
+		}
+	}
+
+	got, _ := p.GetRecords(ctx, testZone)
+	if !equalRecords(got, initial[3:4]) {
+		t.Errorf("Expected remaining records %+v but got %+v", initial[3:4], got)
+	}
+}
+
+func TestListZones(t *testing.T) {
+	ctx := context.Background()
+	var p Provider
+	for _, zone := range []string{"example.net.", "example.com.", "EXAMPLE.com"} {
+		if _, err := p.AppendRecords(ctx, zone, nil); err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+	}
+	zones, err := p.ListZones(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(zones) != 2 || zones[0].Name != "example.com." || zones[1].Name != "example.net." {
+		t.Errorf("Expected zones example.com. and example.net. but got %+v", zones)
+	}
+}
+
+func TestConcurrentAppends(t *testing.T) {
+	ctx := context.Background()
+	var p Provider
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := p.AppendRecords(ctx, testZone, []libdns.Record{
+				{Type: "TXT", Name: "concurrent", Value: fmt.Sprintf("value %d", i)},
+			})
+			if err != nil {
+				t.Errorf("Append %d: expected no error but got: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	got, _ := p.GetRecords(ctx, testZone)
+	if len(got) != 50 {
+		t.Errorf("Expected 50 records but got %d", len(got))
+	}
+	ids := make(map[string]bool)
+	for _, rec := range got {
+		if ids[rec.ID] {
+			t.Errorf("Duplicate ID %s", rec.ID)
+		}
+		ids[rec.ID] = true
+	}
+}
+
+func TestSetRecordsDifferentTypesSameName(t *testing.T) {
+	ctx := context.Background()
+	var p Provider
+
+	_, err := p.AppendRecords(ctx, testZone, []libdns.Record{
+		{Type: "A", Name: "host", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "A", Name: "host", Value: "192.0.2.2", TTL: time.Hour},
+		{Type: "AAAA", Name: "host", Value: "2001:db8::1", TTL: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	_, err = p.SetRecords(ctx, testZone, []libdns.Record{
+		{Type: "A", Name: "host", Value: "192.0.2.3", TTL: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	expect := []libdns.Record{
+		{Type: "AAAA", Name: "host", Value: "2001:db8::1", TTL: time.Hour},
+		{Type: "A", Name: "host", Value: "192.0.2.3", TTL: time.Hour},
+	}
+	got, _ := p.GetRecords(ctx, testZone)
+	if !equalRecords(got, expect) {
+		t.Errorf("Expected records %+v but got %+v", expect, got)
+	}
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	var p Provider
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("host%d", i)
+			_, err := p.AppendRecords(ctx, testZone, []libdns.Record{
+				{Type: "A", Name: name, Value: "192.0.2.1"},
+				{Type: "TXT", Name: name, Value: "temporary"},
+			})
+			if err != nil {
+				t.Errorf("Append %d: expected no error but got: %v", i, err)
+				return
+			}
+			deleted, err := p.DeleteRecords(ctx, testZone, []libdns.Record{{Type: "TXT", Name: name}})
+			if err != nil || len(deleted) != 1 {
+				t.Errorf("Delete %d: expected 1 record deleted but got %d (err=%v)", i, len(deleted), err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	got, _ := p.GetRecords(ctx, testZone)
+	if len(got) != 20 {
+		t.Errorf("Expected 20 records but got %d: %+v", len(got), got)
+	}
+	for _, rec := range got {
+		if rec.Type != "A" {
+			t.Errorf("Expected only A records to remain but found %+v", rec)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package libdns
+
+import "strings"
+
+// IsWildcard reports whether name, relative or fully-qualified, is a
+// wildcard name (RFC 4592): its leftmost label is exactly "*". An
+// asterisk anywhere else, such as in "a.*.b" or "*a.b", has no special
+// meaning in DNS, so such names are not wildcards.
+func IsWildcard(name string) bool {
+	return name == "*" || name == "*." || strings.HasPrefix(name, "*.")
+}
+
+// NameMatches reports whether the record name pattern matches name.
+// Both are relative to the same zone, with "" or "@" denoting the apex,
+// and are compared case-insensitively.
+//
+// If pattern is a wildcard, it matches following RFC 4592 as simplified
+// for record names: "*" stands in for exactly one label, so "*" matches
+// "foo" but not "foo.bar" or the apex, and "*.sub" matches "foo.sub"
+// but not "sub". Other patterns only match the same name.
+func NameMatches(pattern, name string) bool {
+	pattern = strings.TrimSuffix(pattern, ".")
+	name = strings.TrimSuffix(name, ".")
+	if pattern == "@" {
+		pattern = ""
+	}
+	if name == "@" {
+		name = ""
+	}
+	if !IsWildcard(pattern) {
+		return strings.EqualFold(pattern, name)
+	}
+	if name == "" {
+		return false
+	}
+	suffix := strings.TrimPrefix(strings.TrimPrefix(pattern, "*"), ".")
+	first, rest := name, ""
+	if dot := strings.Index(name, "."); dot >= 0 {
+		first, rest = name[:dot], name[dot+1:]
+	}
+	return first != "" && strings.EqualFold(rest, suffix)
+}
+
+// NameFormat is a convention for representing record names.
+type NameFormat int
+
+// The name formats.
+const (
+	// RelativeNames are relative to the zone, e.g. "sub" for
+	// "sub.example.com." in zone "example.com.". This is the
+	// libdns convention.
+	RelativeNames NameFormat = iota
+
+	// AbsoluteNames are fully-qualified, e.g. "sub.example.com.".
+	AbsoluteNames
+)
+
+// String returns the name of the format.
+func (f NameFormat) String() string {
+	switch f {
+	case RelativeNames:
+		return "relative"
+	case AbsoluteNames:
+		return "absolute"
+	}
+	return "unknown"
+}
+
+// ProviderNameFormat returns the format of the record names returned
+// by provider p: the format it declares if it implements NameFormatter,
+// or RelativeNames otherwise.
+func ProviderNameFormat(p any) NameFormat {
+	if nf, ok := p.(NameFormatter); ok {
+		return nf.NameFormat()
+	}
+	return RelativeNames
+}
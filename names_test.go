@@ -0,0 +1,69 @@
+package libdns
+
+import "testing"
+
+func TestIsWildcard(t *testing.T) {
+	for i, test := range []struct {
+		name   string
+		expect bool
+	}{
+		{name: "*", expect: true},
+		{name: "*.sub", expect: true},
+		{name: "*.example.com.", expect: true},
+		{name: "", expect: false},
+		{name: "@", expect: false},
+		{name: "sub", expect: false},
+		{name: "a.*.b", expect: false},
+		{name: "*a.b", expect: false},
+		{name: "a*", expect: false},
+	} {
+		actual := IsWildcard(test.name)
+		if actual != test.expect {
+			t.Errorf("Test %d: NAME=%s - expected %t but got %t", i, test.name, test.expect, actual)
+		}
+	}
+}
+
+func TestNameMatches(t *testing.T) {
+	for i, test := range []struct {
+		pattern, name string
+		expect        bool
+	}{
+		{pattern: "*", name: "foo", expect: true},
+		{pattern: "*", name: "Foo", expect: true},
+		{pattern: "*", name: "*", expect: true},
+		{pattern: "*", name: "foo.bar", expect: false},
+		{pattern: "*", name: "@", expect: false},
+		{pattern: "*", name: "", expect: false},
+		{pattern: "*.sub", name: "foo.sub", expect: true},
+		{pattern: "*.sub", name: "foo.SUB", expect: true},
+		{pattern: "*.sub", name: "sub", expect: false},
+		{pattern: "*.sub", name: "a.foo.sub", expect: false},
+		{pattern: "*.sub", name: "foo.other", expect: false},
+		{pattern: "www", name: "www", expect: true},
+		{pattern: "www", name: "WWW", expect: true},
+		{pattern: "www", name: "foo", expect: false},
+		{pattern: "@", name: "", expect: true},
+		{pattern: "", name: "@", expect: true},
+		{pattern: "foo", name: "*", expect: false},
+	} {
+		actual := NameMatches(test.pattern, test.name)
+		if actual != test.expect {
+			t.Errorf("Test %d: PATTERN=%s NAME=%s - expected %t but got %t",
+				i, test.pattern, test.name, test.expect, actual)
+		}
+	}
+}
+
+type absoluteNamesProvider struct{}
+
+func (absoluteNamesProvider) NameFormat() NameFormat { return AbsoluteNames }
+
+func TestProviderNameFormat(t *testing.T) {
+	if actual := ProviderNameFormat(absoluteNamesProvider{}); actual != AbsoluteNames {
+		t.Errorf("Expected provider declaring absolute names to be %s but got %s", AbsoluteNames, actual)
+	}
+	if actual := ProviderNameFormat(struct{}{}); actual != RelativeNames {
+		t.Errorf("Expected provider without NameFormatter to default to %s but got %s", RelativeNames, actual)
+	}
+}
@@ -0,0 +1,172 @@
+package libdns
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+)
+
+// EnsureAbsoluteTargets returns a copy of recs in which the targets of
+// CNAME, DNAME, NS, PTR, MX and SRV records end with a trailing dot.
+// Some providers return fully-qualified targets without one; this
+// normalizes their output for consumers that expect FQDNs. Targets
+// that are already absolute, or are "." (the root), are not changed.
+func EnsureAbsoluteTargets(recs []Record) []Record {
+	out := make([]Record, len(recs))
+	for i, rec := range recs {
+		out[i] = ensureAbsoluteTarget(rec)
+	}
+	return out
+}
+
+func ensureAbsoluteTarget(rec Record) Record {
+	switch strings.ToUpper(rec.Type) {
+	case "CNAME", "DNAME", "NS", "PTR", "MX":
+		rec.Value = absoluteTarget(rec.Value)
+	case "SRV":
+		// "<port> <target>"
+		if fields := strings.Fields(rec.Value); len(fields) == 2 {
+			rec.Value = fields[0] + " " + absoluteTarget(fields[1])
+		}
+	}
+	return rec
+}
+
+func absoluteTarget(target string) string {
+	if target == "" || strings.HasSuffix(target, ".") {
+		return target
+	}
+	return target + "."
+}
+
+// NormalizeRecord returns a copy of r in canonical form for zone: the
+// type is upper-cased, the name is made relative to zone (with "@" for
+// the apex), and the targets of CNAME, DNAME, NS, PTR, MX and SRV
+// records are made absolute as by EnsureAbsoluteTargets. The TTL and
+// other fields are not changed. It returns an error if the resulting
+// record data is malformed for its type; see ValidateValue.
+func NormalizeRecord(r Record, zone string) (Record, error) {
+	r.Type = strings.ToUpper(r.Type)
+	r.Name = RelativeName(r.Name, zone)
+	if r.Name == "" {
+		r.Name = "@"
+	}
+	r = ensureAbsoluteTarget(r)
+	if err := ValidateValue(r.Type, recordData(r)); err != nil {
+		return Record{}, fmt.Errorf("normalizing %s record %q: %v", r.Type, r.Name, err)
+	}
+	return r, nil
+}
+
+// NormalizeApexNames returns a copy of recs in which records with an
+// empty name are named "@" instead, following the convention for
+// denoting the zone apex. Other names are not changed.
+func NormalizeApexNames(recs []Record) []Record {
+	out := make([]Record, len(recs))
+	for i, rec := range recs {
+		if rec.Name == "" {
+			rec.Name = "@"
+		}
+		out[i] = rec
+	}
+	return out
+}
+
+// CanonicalZone returns the records of zone in a canonical form and
+// order, so that two lists of records for the same zone can be compared
+// with reflect.DeepEqual regardless of how they are ordered or
+// represented. Each record is made canonical as follows:
+//
+//   - The ID is removed.
+//   - The type is upper-cased.
+//   - The name is made relative to zone and lower-cased, with "@" for
+//     the apex.
+//   - Domain names in the data of CNAME, DNAME, NS, PTR, MX and SRV
+//     records are lower-cased and made absolute.
+//   - IP addresses in A and AAAA records are written in their standard
+//     form, and CAA tags are lower-cased with the value quoted.
+//
+// The records are then sorted in the order of SortRecords, with records
+// that it considers equal ordered by TTL and then their other fields, so
+// that the result does not depend on the order of the input; and
+// duplicates are removed. The input is not modified.
+func CanonicalZone(records []Record, zone string) []Record {
+	out := make([]Record, 0, len(records))
+	for _, rec := range records {
+		out = append(out, canonicalRecord(rec, zone))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return compareCanonicalRecords(out[i], out[j]) < 0
+	})
+
+	// an RRset cannot contain the same record twice
+	deduped := out[:0]
+	for i, rec := range out {
+		if i > 0 && rec == out[i-1] {
+			continue
+		}
+		deduped = append(deduped, rec)
+	}
+	return deduped
+}
+
+// compareCanonicalRecords compares a and b in the order of SortRecords,
+// breaking ties by TTL and then by the fields of the records which that
+// order does not consider, returning a negative, zero or positive
+// number.
+func compareCanonicalRecords(a, b Record) int {
+	if c := compareRecords(a, b); c != 0 {
+		return c
+	}
+	if a.TTL != b.TTL {
+		if a.TTL < b.TTL {
+			return -1
+		}
+		return 1
+	}
+	if c := strings.Compare(a.Value, b.Value); c != 0 {
+		return c
+	}
+	if a.Priority != b.Priority {
+		if a.Priority < b.Priority {
+			return -1
+		}
+		return 1
+	}
+	if a.Weight != b.Weight {
+		if a.Weight < b.Weight {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+func canonicalRecord(rec Record, zone string) Record {
+	rec.ID = ""
+	rec.Type = strings.ToUpper(rec.Type)
+	rec.Name = strings.ToLower(RelativeName(qualifiedName(rec.Name, zone), zone))
+	if rec.Name == "" {
+		rec.Name = "@"
+	}
+
+	switch rec.Type {
+	case "A", "AAAA":
+		if ip, err := netip.ParseAddr(strings.TrimSpace(rec.Value)); err == nil {
+			rec.Value = ip.String()
+		}
+	case "CNAME", "DNAME", "NS", "PTR", "MX", "SRV":
+		rec.Value = strings.ToLower(strings.Join(strings.Fields(rec.Value), " "))
+		rec = ensureAbsoluteTarget(rec)
+	case "CAA":
+		// "<flags> <tag> <value>"
+		if flags, tag, value, ok := splitCAA(rec.Value); ok {
+			if unquoted, ok := unquoteCAAValue(value); ok {
+				value = quoteCharacterString(unquoted)
+			}
+			rec.Value = flags + " " + strings.ToLower(tag) + " " + value
+		}
+	}
+	return rec
+}
@@ -0,0 +1,216 @@
+package libdns
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEnsureAbsoluteTargets(t *testing.T) {
+	input := []Record{
+		{Type: "CNAME", Name: "www", Value: "example.com"},
+		{Type: "CNAME", Name: "api", Value: "example.com."},
+		{Type: "DNAME", Name: "old", Value: "new.example.com"},
+		{Type: "NS", Name: "sub", Value: "ns1.example.net"},
+		{Type: "PTR", Name: "1", Value: "host.example.com"},
+		{Type: "MX", Name: "@", Value: "mail.example.com", Priority: 10},
+		{Type: "MX", Name: "@", Value: ".", Priority: 0},
+		{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com", Priority: 10, Weight: 5},
+		{Type: "SRV", Name: "_xmpp._tcp", Value: "5222 xmpp.example.com."},
+		{Type: "TXT", Name: "@", Value: "example.com"},
+		{Type: "A", Name: "www", Value: "192.0.2.1"},
+	}
+	expect := []Record{
+		{Type: "CNAME", Name: "www", Value: "example.com."},
+		{Type: "CNAME", Name: "api", Value: "example.com."},
+		{Type: "DNAME", Name: "old", Value: "new.example.com."},
+		{Type: "NS", Name: "sub", Value: "ns1.example.net."},
+		{Type: "PTR", Name: "1", Value: "host.example.com."},
+		{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10},
+		{Type: "MX", Name: "@", Value: ".", Priority: 0},
+		{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 10, Weight: 5},
+		{Type: "SRV", Name: "_xmpp._tcp", Value: "5222 xmpp.example.com."},
+		{Type: "TXT", Name: "@", Value: "example.com"},
+		{Type: "A", Name: "www", Value: "192.0.2.1"},
+	}
+	original := append([]Record(nil), input...)
+
+	actual := EnsureAbsoluteTargets(input)
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Expected %+v\nbut got  %+v", expect, actual)
+	}
+	if !reflect.DeepEqual(input, original) {
+		t.Errorf("Input was modified")
+	}
+}
+
+func TestNormalizeRecord(t *testing.T) {
+	for i, test := range []struct {
+		input     Record
+		zone      string
+		expect    Record
+		shouldErr bool
+	}{
+		{
+			input:  Record{Type: "CNAME", Name: "www.example.com.", Value: "example.com", TTL: time.Hour},
+			zone:   "example.com.",
+			expect: Record{Type: "CNAME", Name: "www", Value: "example.com.", TTL: time.Hour},
+		},
+		{
+			input:  Record{ID: "42", Type: "mx", Name: "example.com", Value: "mail.example.com", Priority: 10},
+			zone:   "example.com.",
+			expect: Record{ID: "42", Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10},
+		},
+		{
+			input:  Record{Type: "SRV", Name: "_sip._tcp.Example.COM.", Value: "5060 sip.example.com", Priority: 10, Weight: 5},
+			zone:   "example.com.",
+			expect: Record{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 10, Weight: 5},
+		},
+		{
+			input:  Record{Type: "A", Name: "sub", Value: "192.0.2.1", TTL: time.Minute},
+			zone:   "example.com.",
+			expect: Record{Type: "A", Name: "sub", Value: "192.0.2.1", TTL: time.Minute},
+		},
+		{
+			input:     Record{Type: "A", Name: "www", Value: "not-an-ip"},
+			zone:      "example.com.",
+			shouldErr: true,
+		},
+		{
+			input:     Record{Type: "SRV", Name: "_sip._tcp", Value: "sip.example.com"},
+			zone:      "example.com.",
+			shouldErr: true,
+		},
+	} {
+		actual, err := NormalizeRecord(test.input, test.zone)
+		if test.shouldErr {
+			if err == nil {
+				t.Errorf("Test %d: expected error for %+v but got none", i, test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: expected no error but got: %v", i, err)
+			continue
+		}
+		if actual != test.expect {
+			t.Errorf("Test %d: expected %+v but got %+v", i, test.expect, actual)
+		}
+	}
+}
+
+func TestNormalizeApexNames(t *testing.T) {
+	input := []Record{
+		{Type: "A", Name: "", Value: "192.0.2.1"},
+		{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10},
+		{Type: "A", Name: "www", Value: "192.0.2.1"},
+		{Type: "TXT", Name: "", Value: "hello"},
+	}
+	expect := []Record{
+		{Type: "A", Name: "@", Value: "192.0.2.1"},
+		{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10},
+		{Type: "A", Name: "www", Value: "192.0.2.1"},
+		{Type: "TXT", Name: "@", Value: "hello"},
+	}
+	original := append([]Record(nil), input...)
+
+	actual := NormalizeApexNames(input)
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Expected %+v\nbut got  %+v", expect, actual)
+	}
+	if !reflect.DeepEqual(input, original) {
+		t.Errorf("Input was modified")
+	}
+}
+
+func TestCanonicalZoneOrderIndependent(t *testing.T) {
+	recs := []Record{
+		{Type: "A", Name: "x", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "A", Name: "x", Value: "192.0.2.1", TTL: time.Minute},
+		{Type: "A", Name: "x", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "TXT", Name: "x", Value: "hello", TTL: time.Minute},
+	}
+	reversed := make([]Record, len(recs))
+	for i, rec := range recs {
+		reversed[len(recs)-1-i] = rec
+	}
+
+	canon, canonReversed := CanonicalZone(recs, "example.com."), CanonicalZone(reversed, "example.com.")
+	if !reflect.DeepEqual(canon, canonReversed) {
+		t.Errorf("Expected reversed input to canonicalize identically:\n%+v\n%+v", canon, canonReversed)
+	}
+	expect := []Record{
+		{Type: "A", Name: "x", Value: "192.0.2.1", TTL: time.Minute},
+		{Type: "A", Name: "x", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "TXT", Name: "x", Value: "hello", TTL: time.Minute},
+	}
+	if !reflect.DeepEqual(canon, expect) {
+		t.Errorf("Expected %+v but got %+v", expect, canon)
+	}
+}
+
+func TestCanonicalZoneCAAEscapes(t *testing.T) {
+	for i, test := range []struct {
+		value  string
+		expect string
+	}{
+		{value: `0 issue "a\"b"`, expect: `0 issue "a\"b"`},
+		{value: `0 ISSUE a\"b`, expect: `0 issue "a\"b"`},
+		{value: `0 issue "\"ca.example\""`, expect: `0 issue "\"ca.example\""`},
+	} {
+		recs := CanonicalZone([]Record{{Type: "CAA", Name: "@", Value: test.value}}, "example.com.")
+		if recs[0].Value != test.expect {
+			t.Errorf("Test %d: value=%s - expected %s but got %s", i, test.value, test.expect, recs[0].Value)
+		}
+	}
+
+	a := Record{Type: "CAA", Name: "@", Value: `0 issue "\"ca.example\""`}
+	b := Record{Type: "CAA", Name: "@", Value: `0 issue "ca.example"`}
+	if RecordsEqual(a, b, "example.com.") {
+		t.Errorf("Expected CAA values differing by escaped quotes to be unequal")
+	}
+}
+
+func TestCanonicalZone(t *testing.T) {
+	a := []Record{
+		{ID: "1", Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+		{ID: "2", Type: "AAAA", Name: "www", Value: "2001:db8:0:0::1", TTL: time.Hour},
+		{ID: "3", Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10, TTL: time.Hour},
+		{ID: "4", Type: "CNAME", Name: "blog", Value: "www.example.com.", TTL: time.Hour},
+		{ID: "5", Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 10, Weight: 5, TTL: time.Hour},
+		{ID: "6", Type: "CAA", Name: "@", Value: `0 issue "letsencrypt.org"`, TTL: time.Hour},
+		{ID: "7", Type: "TXT", Name: "@", Value: "Hello", TTL: time.Hour},
+	}
+	b := []Record{
+		{Type: "txt", Name: "example.com.", Value: "Hello", TTL: time.Hour},
+		{Type: "caa", Name: "", Value: `0  ISSUE   letsencrypt.org`, TTL: time.Hour},
+		{Type: "srv", Name: "_SIP._tcp.example.com.", Value: "5060  SIP.example.com", Priority: 10, Weight: 5, TTL: time.Hour},
+		{Type: "cname", Name: "Blog", Value: "WWW.example.com", TTL: time.Hour},
+		{Type: "mx", Name: "", Value: "Mail.Example.COM", Priority: 10, TTL: time.Hour},
+		{Type: "aaaa", Name: "WWW", Value: "2001:DB8::1", TTL: time.Hour},
+		{Type: "a", Name: "www.example.com.", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour}, // duplicate
+	}
+
+	canonA, canonB := CanonicalZone(a, "example.com."), CanonicalZone(b, "example.com.")
+	if !reflect.DeepEqual(canonA, canonB) {
+		t.Errorf("Expected equivalent zones to canonicalize identically:\n%+v\n%+v", canonA, canonB)
+	}
+	if len(canonA) != len(a) {
+		t.Errorf("Expected %d records but got %d", len(a), len(canonA))
+	}
+	if a[0].ID != "1" || b[0].Type != "txt" {
+		t.Errorf("Input was modified")
+	}
+
+	// records that differ in substance remain different
+	c := append([]Record(nil), a...)
+	c[0].TTL = time.Minute
+	if reflect.DeepEqual(CanonicalZone(c, "example.com."), canonA) {
+		t.Errorf("Expected zones with different TTLs to canonicalize differently")
+	}
+	c[0].TTL, c[6].Value = time.Hour, "hello"
+	if reflect.DeepEqual(CanonicalZone(c, "example.com."), canonA) {
+		t.Errorf("Expected zones with different TXT values to canonicalize differently")
+	}
+}
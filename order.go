@@ -0,0 +1,114 @@
+package libdns
+
+import (
+	"sort"
+	"strings"
+)
+
+// ReorderToInput returns the records in output, which were returned by
+// a provider for the records in input (for example, by AppendRecords),
+// in the order of their counterparts in input. Providers whose APIs do
+// not preserve order can use it before returning records.
+//
+// A record in output is the counterpart of one in input if they have
+// the same name, type, priority, weight and value, compared as by
+// RecordsEqual; IDs and TTLs are ignored since providers may assign or
+// adjust them. Each output record is matched at most once. Records in
+// output without a counterpart are placed at the end, in their
+// original order.
+func ReorderToInput(input, output []Record) []Record {
+	used := make([]bool, len(output))
+	reordered := make([]Record, 0, len(output))
+	for _, in := range input {
+		for j, out := range output {
+			if !used[j] && sameRecordIdentity(in, out) {
+				used[j] = true
+				reordered = append(reordered, out)
+				break
+			}
+		}
+	}
+	for j, out := range output {
+		if !used[j] {
+			reordered = append(reordered, out)
+		}
+	}
+	return reordered
+}
+
+// sameRecordIdentity reports whether a and b describe the same record,
+// ignoring their IDs and TTLs.
+func sameRecordIdentity(a, b Record) bool {
+	return rrsetKey(a) == rrsetKey(b) &&
+		a.Priority == b.Priority &&
+		a.Weight == b.Weight &&
+		valuesEqual(strings.ToUpper(a.Type), a.Value, b.Value)
+}
+
+// SortRecords sorts records in place into a canonical order, so that
+// lists of records, such as the output of separate GetRecords calls,
+// can be compared and diffed. Records are ordered by name, comparing
+// labels from right to left so that names in the same subtree are
+// grouped together and the apex comes first (as in the canonical order
+// of RFC 4034 section 6.1); then by type, except that an SOA record
+// comes before all other types at its name; then by data, as it would
+// be written in a zone file. Names and types are compared
+// case-insensitively. Records which compare equal keep their relative
+// order.
+func SortRecords(records []Record) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return compareRecords(records[i], records[j]) < 0
+	})
+}
+
+// compareRecords compares a and b in the order of SortRecords,
+// returning a negative, zero or positive number.
+func compareRecords(a, b Record) int {
+	keyA, keyB := rrsetKey(a), rrsetKey(b)
+	if c := compareNames(keyA.Name, keyB.Name); c != 0 {
+		return c
+	}
+	if c := compareTypes(keyA.Type, keyB.Type); c != 0 {
+		return c
+	}
+	return strings.Compare(recordData(a), recordData(b))
+}
+
+// compareTypes compares upper-cased record types alphabetically, except
+// that SOA sorts first.
+func compareTypes(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "SOA" {
+		return -1
+	}
+	if b == "SOA" {
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// compareNames compares lower-cased relative names label by label from
+// right to left. The apex, "@", sorts before all other names.
+func compareNames(a, b string) int {
+	labelsA, labelsB := reversedLabels(a), reversedLabels(b)
+	for i := 0; i < len(labelsA) && i < len(labelsB); i++ {
+		if c := strings.Compare(labelsA[i], labelsB[i]); c != 0 {
+			return c
+		}
+	}
+	return len(labelsA) - len(labelsB)
+}
+
+func reversedLabels(name string) []string {
+	name = strings.TrimSuffix(name, ".")
+	if name == "@" || name == "" {
+		return nil
+	}
+	labels := strings.Split(name, ".")
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+	return labels
+}
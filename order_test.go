@@ -0,0 +1,116 @@
+package libdns
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReorderToInput(t *testing.T) {
+	input := []Record{
+		{Type: "A", Name: "www", Value: "192.0.2.1"},
+		{Type: "A", Name: "www", Value: "192.0.2.2"},
+		{Type: "TXT", Name: "@", Value: "hello"},
+		{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10},
+		{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 20},
+	}
+	output := []Record{
+		{ID: "5", Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 20, TTL: time.Hour},
+		{ID: "3", Type: "TXT", Name: "", Value: "hello", TTL: time.Hour},
+		{ID: "9", Type: "AAAA", Name: "www", Value: "2001:db8::1", TTL: time.Hour},
+		{ID: "2", Type: "A", Name: "WWW", Value: "192.0.2.2", TTL: time.Hour},
+		{ID: "4", Type: "MX", Name: "@", Value: "Mail.Example.com", Priority: 10, TTL: time.Hour},
+		{ID: "1", Type: "a", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+	}
+	expectIDs := []string{"1", "2", "3", "4", "5", "9"}
+
+	actual := ReorderToInput(input, output)
+	var actualIDs []string
+	for _, rec := range actual {
+		actualIDs = append(actualIDs, rec.ID)
+	}
+	if !reflect.DeepEqual(actualIDs, expectIDs) {
+		t.Errorf("Expected records in order %v but got %v", expectIDs, actualIDs)
+	}
+}
+
+func TestReorderToInputDuplicates(t *testing.T) {
+	// identical input records are each matched to a distinct output record
+	input := []Record{
+		{Type: "TXT", Name: "_acme-challenge", Value: "token"},
+		{Type: "TXT", Name: "_acme-challenge", Value: "token"},
+	}
+	output := []Record{
+		{ID: "a", Type: "TXT", Name: "_acme-challenge", Value: "token"},
+		{ID: "b", Type: "TXT", Name: "_acme-challenge", Value: "token"},
+	}
+	actual := ReorderToInput(input, output)
+	if !reflect.DeepEqual(actual, output) {
+		t.Errorf("Expected %+v but got %+v", output, actual)
+	}
+}
+
+func TestSortRecords(t *testing.T) {
+	recs := []Record{
+		{Type: "A", Name: "www", Value: "192.0.2.2"},
+		{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 20, Weight: 5},
+		{Type: "TXT", Name: "@", Value: "hello"},
+		{Type: "A", Name: "a.sub", Value: "192.0.2.3"},
+		{Type: "MX", Name: "", Value: "mail.example.com.", Priority: 10},
+		{Type: "A", Name: "WWW", Value: "192.0.2.1"},
+		{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 10, Weight: 5},
+		{Type: "A", Name: "sub", Value: "192.0.2.4"},
+		{Type: "aaaa", Name: "www", Value: "2001:db8::1"},
+		{Type: "A", Name: "b", Value: "192.0.2.5"},
+	}
+	expect := []Record{
+		{Type: "MX", Name: "", Value: "mail.example.com.", Priority: 10},
+		{Type: "TXT", Name: "@", Value: "hello"},
+		{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 10, Weight: 5},
+		{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 20, Weight: 5},
+		{Type: "A", Name: "b", Value: "192.0.2.5"},
+		{Type: "A", Name: "sub", Value: "192.0.2.4"},
+		{Type: "A", Name: "a.sub", Value: "192.0.2.3"},
+		{Type: "A", Name: "WWW", Value: "192.0.2.1"},
+		{Type: "A", Name: "www", Value: "192.0.2.2"},
+		{Type: "aaaa", Name: "www", Value: "2001:db8::1"},
+	}
+
+	SortRecords(recs)
+	if !reflect.DeepEqual(recs, expect) {
+		t.Errorf("Expected:\n%+v\nbut got:\n%+v", expect, recs)
+	}
+
+	// sorting a shuffled copy gives the same order
+	shuffled := []Record{expect[9], expect[3], expect[0], expect[7], expect[5], expect[1], expect[8], expect[2], expect[6], expect[4]}
+	SortRecords(shuffled)
+	if !reflect.DeepEqual(shuffled, expect) {
+		t.Errorf("Expected deterministic order:\n%+v\nbut got:\n%+v", expect, shuffled)
+	}
+}
+
+func TestSortRecordsSOAAndWildcard(t *testing.T) {
+	recs := []Record{
+		{Type: "A", Name: "x.sub", Value: "192.0.2.3"},
+		{Type: "NS", Name: "@", Value: "ns1.example.com."},
+		{Type: "A", Name: "*", Value: "192.0.2.1"},
+		{Type: "SOA", Name: "@", Value: "ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 3600"},
+		{Type: "A", Name: "*.sub", Value: "192.0.2.2"},
+		{Type: "A", Name: "a", Value: "192.0.2.4"},
+		{Type: "A", Name: "@", Value: "192.0.2.5"},
+	}
+	expect := []Record{
+		{Type: "SOA", Name: "@", Value: "ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 3600"},
+		{Type: "A", Name: "@", Value: "192.0.2.5"},
+		{Type: "NS", Name: "@", Value: "ns1.example.com."},
+		{Type: "A", Name: "*", Value: "192.0.2.1"},
+		{Type: "A", Name: "a", Value: "192.0.2.4"},
+		{Type: "A", Name: "*.sub", Value: "192.0.2.2"},
+		{Type: "A", Name: "x.sub", Value: "192.0.2.3"},
+	}
+
+	SortRecords(recs)
+	if !reflect.DeepEqual(recs, expect) {
+		t.Errorf("Expected:\n%+v\nbut got:\n%+v", expect, recs)
+	}
+}
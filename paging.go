@@ -0,0 +1,30 @@
+package libdns
+
+import (
+	"context"
+	"fmt"
+)
+
+// CollectAllPages gets all the records in zone from p, requesting pages
+// until there are no more, and returns them in the order they were
+// received.
+func CollectAllPages(ctx context.Context, p RecordPager, zone string) ([]Record, error) {
+	var all []Record
+	var token string
+	seen := make(map[string]struct{})
+	for {
+		page, next, err := p.GetRecordsPage(ctx, zone, token)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if next == "" {
+			return all, nil
+		}
+		if _, ok := seen[next]; ok {
+			return nil, fmt.Errorf("page token %q repeated", next)
+		}
+		seen[next] = struct{}{}
+		token = next
+	}
+}
@@ -0,0 +1,76 @@
+package libdns
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// fakePager is a RecordPager that returns fixed pages, keyed by the token
+// that requests them.
+type fakePager struct {
+	pages map[string][]Record
+	next  map[string]string
+	calls []string
+}
+
+func (p *fakePager) GetRecordsPage(ctx context.Context, zone, pageToken string) ([]Record, string, error) {
+	p.calls = append(p.calls, pageToken)
+	page, ok := p.pages[pageToken]
+	if !ok {
+		return nil, "", fmt.Errorf("invalid page token %q", pageToken)
+	}
+	return page, p.next[pageToken], nil
+}
+
+func TestCollectAllPages(t *testing.T) {
+	p := &fakePager{
+		pages: map[string][]Record{
+			"": {
+				{Type: "A", Name: "a", Value: "192.0.2.1"},
+				{Type: "A", Name: "b", Value: "192.0.2.2"},
+			},
+			"page2": {
+				{Type: "A", Name: "c", Value: "192.0.2.3"},
+				{Type: "A", Name: "d", Value: "192.0.2.4"},
+			},
+			"page3": {
+				{Type: "A", Name: "e", Value: "192.0.2.5"},
+			},
+		},
+		next: map[string]string{"": "page2", "page2": "page3"},
+	}
+
+	actual, err := CollectAllPages(context.Background(), p, "example.com.")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	expect := append(append(append([]Record(nil), p.pages[""]...), p.pages["page2"]...), p.pages["page3"]...)
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Expected %+v but got %+v", expect, actual)
+	}
+	if expectCalls := []string{"", "page2", "page3"}; !reflect.DeepEqual(p.calls, expectCalls) {
+		t.Errorf("Expected pages to be requested with tokens %q but got %q", expectCalls, p.calls)
+	}
+}
+
+func TestCollectAllPagesErrors(t *testing.T) {
+	// a failing page
+	p := &fakePager{
+		pages: map[string][]Record{"": {{Type: "A", Name: "a", Value: "192.0.2.1"}}},
+		next:  map[string]string{"": "missing"},
+	}
+	if _, err := CollectAllPages(context.Background(), p, "example.com."); err == nil {
+		t.Errorf("Expected error from failing page but got none")
+	}
+
+	// a provider that never stops paging
+	p = &fakePager{
+		pages: map[string][]Record{"": nil, "loop": nil},
+		next:  map[string]string{"": "loop", "loop": "loop"},
+	}
+	if _, err := CollectAllPages(context.Background(), p, "example.com."); err == nil {
+		t.Errorf("Expected error from repeated page token but got none")
+	}
+}
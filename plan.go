@@ -0,0 +1,160 @@
+package libdns
+
+// OperationKind is the kind of change an Operation makes to a zone,
+// corresponding to a method of one of the libdns interfaces.
+type OperationKind string
+
+// The kinds of operations.
+const (
+	OpAppend OperationKind = "append" // RecordAppender.AppendRecords
+	OpSet    OperationKind = "set"    // RecordSetter.SetRecords
+	OpDelete OperationKind = "delete" // RecordDeleter.DeleteRecords
+)
+
+// Operation describes a single call to a libdns interface method, with
+// the records that would be passed to it.
+type Operation struct {
+	Kind    OperationKind
+	Records []Record
+}
+
+// ChangeBatch is a set of changes to be made to a zone, grouped by the
+// kind of operation that makes them.
+type ChangeBatch struct {
+	Append []Record // records to add
+	Set    []Record // records whose RRsets are to be updated
+	Delete []Record // records to remove
+}
+
+// PlanOperations returns the calls that would reconcile the records of
+// zone from current to desired, in the order they should be executed:
+//
+//  1. OpDelete for all records in RRsets that are in current but not
+//     in desired;
+//  2. OpSet for all records in RRsets that are in both, but whose
+//     records differ (including by TTL only);
+//  3. OpAppend for all records in RRsets that are only in desired.
+//
+// Deletions come first so that, for example, an A record can be
+// replaced by a CNAME at the same name. RRsets that are the same in
+// current and desired (per RecordsEqual) are left alone, and kinds of
+// operations with no records are omitted, so an empty plan means the
+// zone is already in the desired state. Records to delete are taken
+// from current, so they retain any provider-specific IDs.
+func PlanOperations(current, desired []Record, zone string) []Operation {
+	toAppend, toSet, toDelete := diffRRSets(current, desired, zone)
+
+	var ops []Operation
+	for _, op := range []Operation{
+		{Kind: OpDelete, Records: toDelete},
+		{Kind: OpSet, Records: toSet},
+		{Kind: OpAppend, Records: toAppend},
+	} {
+		if len(op.Records) > 0 {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// DiffRecords compares the existing records of zone with the desired
+// records, RRset by RRset, and returns the records to pass to
+// AppendRecords, SetRecords and DeleteRecords to make the zone match
+// the desired state:
+//
+//   - toAppend holds the records of RRsets only in desired;
+//   - toSet holds the desired records of RRsets in both whose records
+//     differ, including RRsets that differ only by TTL, since SetRecords
+//     replaces a whole RRset;
+//   - toDelete holds the existing records of RRsets only in existing,
+//     so they retain any provider-specific IDs.
+//
+// RRsets that are the same in existing and desired, as compared by
+// RecordsEqual, are omitted from all three. Records in other RRsets at
+// the same name are not affected. It is the same comparison as used by
+// PlanOperations, which also orders the calls.
+func DiffRecords(existing, desired []Record, zone string) (toAppend, toSet, toDelete []Record) {
+	return diffRRSets(existing, desired, zone)
+}
+
+// ChangedRecords compares two snapshots of the records in zone, such as
+// the results of two GetRecords calls, and returns the changes between
+// them by RRset. It is DiffRecords with before as the existing records
+// and after as the desired ones, with its results named for reporting
+// changes rather than applying them: added is toAppend, removed is
+// toDelete, and modified is toSet.
+func ChangedRecords(before, after []Record, zone string) (added, removed, modified []Record) {
+	added, modified, removed = DiffRecords(before, after, zone)
+	return added, removed, modified
+}
+
+// diffRRSets compares current and desired RRset by RRset, returning the
+// records of RRsets only in desired, the desired records of RRsets that
+// differ, and the current records of RRsets only in current. RRsets are
+// returned in the order they first appear in the input.
+func diffRRSets(current, desired []Record, zone string) (toAppend, toSet, toDelete []Record) {
+	currentKeys, currentSets := groupRRSetsInZone(current, zone)
+	desiredKeys, desiredSets := groupRRSetsInZone(desired, zone)
+
+	for _, key := range desiredKeys {
+		have, ok := currentSets[key]
+		switch {
+		case !ok:
+			toAppend = append(toAppend, desiredSets[key]...)
+		case !rrsetsEqual(have, desiredSets[key], zone):
+			toSet = append(toSet, desiredSets[key]...)
+		}
+	}
+	for _, key := range currentKeys {
+		if _, ok := desiredSets[key]; !ok {
+			toDelete = append(toDelete, currentSets[key]...)
+		}
+	}
+
+	return
+}
+
+// groupRRSetsInZone is like GroupByRRSet, but resolves names in zone
+// so that relative and fully-qualified names group together, and also
+// returns the keys in the order they first appear.
+func groupRRSetsInZone(recs []Record, zone string) ([]RRSetKey, map[RRSetKey][]Record) {
+	var keys []RRSetKey
+	groups := make(map[RRSetKey][]Record)
+	for _, rec := range recs {
+		key := rrsetKeyInZone(rec, zone)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], rec)
+	}
+	return keys, groups
+}
+
+// rrsetsEqual reports whether a and b contain the same records, in any
+// order, as determined by RecordsEqual.
+func rrsetsEqual(a, b []Record, zone string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	matched := make([]bool, len(b))
+	for _, recA := range a {
+		found := false
+		for j, recB := range b {
+			if !matched[j] && RecordsEqual(recA, recB, zone) {
+				matched[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// rrsetKeyInZone is like rrsetKey, but first resolves the record's name
+// against zone, so that relative and fully-qualified names are equal.
+func rrsetKeyInZone(rec Record, zone string) RRSetKey {
+	rec.Name = RelativeName(qualifiedName(rec.Name, zone), zone)
+	return rrsetKey(rec)
+}
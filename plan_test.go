@@ -0,0 +1,127 @@
+package libdns
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPlanOperations(t *testing.T) {
+	current := []Record{
+		{ID: "1", Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+		{ID: "2", Type: "A", Name: "www", Value: "192.0.2.2", TTL: time.Hour},
+		{ID: "3", Type: "TXT", Name: "old", Value: "remove me", TTL: time.Hour},
+		{ID: "4", Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10, TTL: time.Hour},
+		{ID: "5", Type: "CNAME", Name: "api", Value: "www.example.com.", TTL: time.Hour},
+	}
+	desired := []Record{
+		{Type: "MX", Name: "example.com.", Value: "mail.example.com", Priority: 10, TTL: time.Hour},
+		{Type: "A", Name: "www", Value: "192.0.2.2", TTL: time.Hour},
+		{Type: "A", Name: "www", Value: "192.0.2.3", TTL: time.Hour},
+		{Type: "AAAA", Name: "www", Value: "2001:db8::1", TTL: time.Hour},
+		{Type: "CNAME", Name: "API", Value: "www.example.com.", TTL: time.Hour},
+	}
+
+	expect := []Operation{
+		{Kind: OpDelete, Records: []Record{current[2]}},
+		{Kind: OpSet, Records: []Record{desired[1], desired[2]}},
+		{Kind: OpAppend, Records: []Record{desired[3]}},
+	}
+
+	actual := PlanOperations(current, desired, "example.com.")
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Expected %+v\nbut got  %+v", expect, actual)
+	}
+}
+
+func TestPlanOperationsNoChanges(t *testing.T) {
+	recs := []Record{
+		{Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "A", Name: "www", Value: "192.0.2.2", TTL: time.Hour},
+	}
+	reordered := []Record{recs[1], recs[0]}
+	if ops := PlanOperations(recs, reordered, "example.com."); len(ops) != 0 {
+		t.Errorf("Expected no operations but got %+v", ops)
+	}
+}
+
+func TestPlanOperationsTTLOnly(t *testing.T) {
+	current := []Record{{Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour}}
+	desired := []Record{{Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Minute}}
+	expect := []Operation{{Kind: OpSet, Records: desired}}
+	if actual := PlanOperations(current, desired, "example.com."); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Expected %+v\nbut got  %+v", expect, actual)
+	}
+}
+
+func TestDiffRecords(t *testing.T) {
+	existing := []Record{
+		{ID: "1", Type: "A", Name: "@", Value: "192.0.2.1", TTL: time.Hour},
+		{ID: "2", Type: "A", Name: "@", Value: "192.0.2.2", TTL: time.Hour},
+		{ID: "3", Type: "TXT", Name: "@", Value: "v=spf1 -all", TTL: time.Hour},
+		{ID: "4", Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10, TTL: time.Hour},
+		{ID: "5", Type: "MX", Name: "@", Value: "mail2.example.com.", Priority: 20, TTL: time.Hour},
+		{ID: "6", Type: "CNAME", Name: "old", Value: "www.example.com.", TTL: time.Hour},
+		{ID: "7", Type: "A", Name: "www", Value: "192.0.2.3", TTL: time.Hour},
+	}
+	desired := []Record{
+		// A at apex: one member replaced
+		{Type: "A", Name: "@", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "A", Name: "@", Value: "192.0.2.4", TTL: time.Hour},
+		// TXT at apex: TTL only
+		{Type: "TXT", Name: "", Value: "v=spf1 -all", TTL: 5 * time.Minute},
+		// MX at apex: unchanged, in a different order and name format
+		{Type: "MX", Name: "example.com.", Value: "mail2.example.com.", Priority: 20, TTL: time.Hour},
+		{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10, TTL: time.Hour},
+		// www: unchanged A, new AAAA
+		{Type: "A", Name: "www", Value: "192.0.2.3", TTL: time.Hour},
+		{Type: "AAAA", Name: "www", Value: "2001:db8::1", TTL: time.Hour},
+	}
+
+	toAppend, toSet, toDelete := DiffRecords(existing, desired, "example.com.")
+	if expect := desired[6:7]; !reflect.DeepEqual(toAppend, expect) {
+		t.Errorf("Expected to append %+v but got %+v", expect, toAppend)
+	}
+	if expect := desired[0:3]; !reflect.DeepEqual(toSet, expect) {
+		t.Errorf("Expected to set %+v but got %+v", expect, toSet)
+	}
+	if expect := existing[5:6]; !reflect.DeepEqual(toDelete, expect) {
+		t.Errorf("Expected to delete %+v but got %+v", expect, toDelete)
+	}
+
+	toAppend, toSet, toDelete = DiffRecords(existing, existing, "example.com.")
+	if toAppend != nil || toSet != nil || toDelete != nil {
+		t.Errorf("Expected no changes but got append=%+v set=%+v delete=%+v", toAppend, toSet, toDelete)
+	}
+}
+
+func TestChangedRecords(t *testing.T) {
+	before := []Record{
+		{ID: "1", Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+		{ID: "2", Type: "TXT", Name: "@", Value: "hello", TTL: time.Hour},
+		{ID: "3", Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10, TTL: time.Hour},
+		{ID: "4", Type: "CNAME", Name: "old", Value: "www.example.com.", TTL: time.Hour},
+	}
+	after := []Record{
+		{ID: "1", Type: "A", Name: "www.example.com.", Value: "192.0.2.1", TTL: time.Hour},
+		{ID: "2", Type: "TXT", Name: "@", Value: "hello", TTL: 5 * time.Minute},
+		{ID: "3", Type: "MX", Name: "@", Value: "Mail.Example.com", Priority: 10, TTL: time.Hour},
+		{ID: "5", Type: "AAAA", Name: "www", Value: "2001:db8::1", TTL: time.Hour},
+	}
+
+	added, removed, modified := ChangedRecords(before, after, "example.com.")
+	if expect := after[3:4]; !reflect.DeepEqual(added, expect) {
+		t.Errorf("Expected added %+v but got %+v", expect, added)
+	}
+	if expect := before[3:4]; !reflect.DeepEqual(removed, expect) {
+		t.Errorf("Expected removed %+v but got %+v", expect, removed)
+	}
+	if expect := after[1:2]; !reflect.DeepEqual(modified, expect) {
+		t.Errorf("Expected modified %+v but got %+v", expect, modified)
+	}
+
+	added, removed, modified = ChangedRecords(before, before, "example.com.")
+	if added != nil || removed != nil || modified != nil {
+		t.Errorf("Expected no changes but got added=%+v removed=%+v modified=%+v", added, removed, modified)
+	}
+}
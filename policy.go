@@ -0,0 +1,61 @@
+package libdns
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SafetyPolicy describes limits on the changes that automated tools may
+// make to a zone, to guard against catastrophic mistakes such as
+// deleting most of a zone or breaking its delegation. The zero value
+// allows all changes.
+type SafetyPolicy struct {
+	// MaxDeletes is the maximum number of records that may be deleted.
+	// If zero, there is no limit.
+	MaxDeletes int
+
+	// ProtectApexNS forbids any change to the NS records at the apex,
+	// which would affect the delegation of the zone.
+	ProtectApexNS bool
+
+	// ProtectedTypes are record types that may not be changed at any
+	// name without confirmation, for example "NS" or "DS". Violations
+	// of this rule can be presented to a user for confirmation.
+	ProtectedTypes []string
+}
+
+// CheckPolicy evaluates the changes in batch against policy, returning
+// an error for each violation, or nil if there are none. Record names
+// are expected to be relative, with "" or "@" denoting the apex.
+func CheckPolicy(batch ChangeBatch, policy SafetyPolicy) []error {
+	var errs []error
+
+	if policy.MaxDeletes > 0 && len(batch.Delete) > policy.MaxDeletes {
+		errs = append(errs, fmt.Errorf("%d records would be deleted, but at most %d may be", len(batch.Delete), policy.MaxDeletes))
+	}
+
+	for _, op := range []struct {
+		kind OperationKind
+		recs []Record
+	}{
+		{OpAppend, batch.Append},
+		{OpSet, batch.Set},
+		{OpDelete, batch.Delete},
+	} {
+		for _, rec := range op.recs {
+			key := rrsetKey(rec)
+			if policy.ProtectApexNS && key.Name == "@" && key.Type == "NS" {
+				errs = append(errs, fmt.Errorf("%s would change apex NS record %s", op.kind, rec.Value))
+				continue
+			}
+			for _, typ := range policy.ProtectedTypes {
+				if strings.EqualFold(typ, key.Type) {
+					errs = append(errs, fmt.Errorf("%s would change protected %s record %s", op.kind, key.Type, key.Name))
+					break
+				}
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,62 @@
+package libdns
+
+import "testing"
+
+func TestCheckPolicy(t *testing.T) {
+	policy := SafetyPolicy{
+		MaxDeletes:     2,
+		ProtectApexNS:  true,
+		ProtectedTypes: []string{"ds"},
+	}
+
+	for i, test := range []struct {
+		batch      ChangeBatch
+		expectErrs int
+	}{
+		{
+			batch: ChangeBatch{
+				Append: []Record{{Type: "A", Name: "www", Value: "192.0.2.1"}},
+				Set:    []Record{{Type: "NS", Name: "sub", Value: "ns1.example.net."}},
+				Delete: []Record{{Type: "TXT", Name: "@", Value: "old"}},
+			},
+			expectErrs: 0,
+		},
+		{
+			// too many deletions
+			batch: ChangeBatch{
+				Delete: []Record{
+					{Type: "A", Name: "a", Value: "192.0.2.1"},
+					{Type: "A", Name: "b", Value: "192.0.2.2"},
+					{Type: "A", Name: "c", Value: "192.0.2.3"},
+				},
+			},
+			expectErrs: 1,
+		},
+		{
+			// apex NS changes, however the apex is named
+			batch: ChangeBatch{
+				Set:    []Record{{Type: "NS", Name: "@", Value: "ns1.example.net."}},
+				Delete: []Record{{Type: "ns", Name: "", Value: "ns2.example.com."}},
+			},
+			expectErrs: 2,
+		},
+		{
+			// protected type
+			batch: ChangeBatch{
+				Append: []Record{{Type: "DS", Name: "sub", Value: "12345 13 2 abcdef"}},
+			},
+			expectErrs: 1,
+		},
+	} {
+		errs := CheckPolicy(test.batch, policy)
+		if len(errs) != test.expectErrs {
+			t.Errorf("Test %d: expected %d errors but got %d: %v", i, test.expectErrs, len(errs), errs)
+		}
+	}
+
+	// the zero policy allows anything
+	batch := ChangeBatch{Delete: []Record{{Type: "NS", Name: "@", Value: "ns1.example.com."}}}
+	if errs := CheckPolicy(batch, SafetyPolicy{}); errs != nil {
+		t.Errorf("Expected zero policy to allow all changes but got: %v", errs)
+	}
+}
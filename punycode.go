@@ -0,0 +1,237 @@
+package libdns
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// acePrefix is the prefix of labels of internationalized domain names
+// in their ASCII-compatible encoding (RFC 5890 section 2.3.2.5).
+const acePrefix = "xn--"
+
+// ToPunycode converts an internationalized domain name to its ASCII
+// form by encoding each label that contains non-ASCII characters with
+// Punycode (RFC 3492) and adding the "xn--" prefix; for example,
+// "www.müller.de." becomes "www.xn--mller-kva.de.". Encoded labels are
+// lower-cased first. ASCII labels are returned unchanged.
+//
+// This performs no other IDNA mapping or validation.
+func ToPunycode(name string) (string, error) {
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			continue
+		}
+		encoded, err := punyEncode(strings.ToLower(label))
+		if err != nil {
+			return "", fmt.Errorf("encoding label %q: %v", label, err)
+		}
+		labels[i] = acePrefix + encoded
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// FromPunycode converts a domain name in ASCII form to its Unicode form
+// by decoding each label that has the "xn--" prefix; for example,
+// "www.xn--mller-kva.de." becomes "www.müller.de.". Encoded labels are
+// case-insensitive and decode to lower case. Other labels are returned
+// unchanged.
+func FromPunycode(name string) (string, error) {
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if len(label) < len(acePrefix) || !strings.EqualFold(label[:len(acePrefix)], acePrefix) {
+			continue
+		}
+		decoded, err := punyDecode(strings.ToLower(label[len(acePrefix):]))
+		if err != nil {
+			return "", fmt.Errorf("decoding label %q: %v", label, err)
+		}
+		labels[i] = decoded
+	}
+	return strings.Join(labels, "."), nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// Bootstring parameters for Punycode (RFC 3492 section 5).
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+)
+
+var errPunyOverflow = fmt.Errorf("overflow")
+
+// punyEncode encodes s with Punycode, without the ACE prefix.
+func punyEncode(s string) (string, error) {
+	runes := []rune(s)
+
+	var out []byte
+	for _, r := range runes {
+		if r < 0x80 {
+			out = append(out, byte(r))
+		}
+	}
+	b := int32(len(out))
+	h := b
+	if b > 0 {
+		out = append(out, '-')
+	}
+
+	n, delta, bias := int32(punyInitialN), int32(0), int32(punyInitialBias)
+	for h < int32(len(runes)) {
+		m := int32(math.MaxInt32)
+		for _, r := range runes {
+			if r >= n && r < m {
+				m = r
+			}
+		}
+		if m-n > (math.MaxInt32-delta)/(h+1) {
+			return "", errPunyOverflow
+		}
+		delta += (m - n) * (h + 1)
+		n = m
+
+		for _, r := range runes {
+			if r < n {
+				if delta == math.MaxInt32 {
+					return "", errPunyOverflow
+				}
+				delta++
+			}
+			if r != n {
+				continue
+			}
+			q := delta
+			for k := int32(punyBase); ; k += punyBase {
+				t := punyThreshold(k, bias)
+				if q < t {
+					break
+				}
+				out = append(out, punyEncodeDigit(t+(q-t)%(punyBase-t)))
+				q = (q - t) / (punyBase - t)
+			}
+			out = append(out, punyEncodeDigit(q))
+			bias = punyAdapt(delta, h+1, h == b)
+			delta = 0
+			h++
+		}
+		delta++
+		n++
+	}
+
+	return string(out), nil
+}
+
+// punyDecode decodes s, which is Punycode without the ACE prefix.
+func punyDecode(s string) (string, error) {
+	var output []rune
+	pos := 0
+	if i := strings.LastIndexByte(s, '-'); i >= 0 {
+		for j := 0; j < i; j++ {
+			if s[j] >= 0x80 {
+				return "", fmt.Errorf("non-ASCII character in input")
+			}
+			output = append(output, rune(s[j]))
+		}
+		pos = i + 1
+	}
+
+	n, i, bias := int32(punyInitialN), int32(0), int32(punyInitialBias)
+	for pos < len(s) {
+		oldi, w := i, int32(1)
+		for k := int32(punyBase); ; k += punyBase {
+			if pos >= len(s) {
+				return "", fmt.Errorf("truncated input")
+			}
+			digit, ok := punyDecodeDigit(s[pos])
+			if !ok {
+				return "", fmt.Errorf("invalid character %q", s[pos])
+			}
+			pos++
+			if digit > (math.MaxInt32-i)/w {
+				return "", errPunyOverflow
+			}
+			i += digit * w
+			t := punyThreshold(k, bias)
+			if digit < t {
+				break
+			}
+			if w > math.MaxInt32/(punyBase-t) {
+				return "", errPunyOverflow
+			}
+			w *= punyBase - t
+		}
+
+		x := int32(len(output) + 1)
+		bias = punyAdapt(i-oldi, x, oldi == 0)
+		if i/x > math.MaxInt32-n {
+			return "", errPunyOverflow
+		}
+		n += i / x
+		i %= x
+
+		output = append(output, 0)
+		copy(output[i+1:], output[i:])
+		output[i] = n
+		i++
+	}
+
+	return string(output), nil
+}
+
+func punyThreshold(k, bias int32) int32 {
+	switch {
+	case k <= bias+punyTMin:
+		return punyTMin
+	case k >= bias+punyTMax:
+		return punyTMax
+	}
+	return k - bias
+}
+
+func punyAdapt(delta, numPoints int32, firstTime bool) int32 {
+	if firstTime {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / numPoints
+	k := int32(0)
+	for delta > ((punyBase-punyTMin)*punyTMax)/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+	return k + (punyBase-punyTMin+1)*delta/(delta+punySkew)
+}
+
+func punyEncodeDigit(d int32) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
+
+func punyDecodeDigit(c byte) (int32, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int32(c-'0') + 26, true
+	case c >= 'a' && c <= 'z':
+		return int32(c - 'a'), true
+	case c >= 'A' && c <= 'Z':
+		return int32(c - 'A'), true
+	}
+	return 0, false
+}
@@ -0,0 +1,61 @@
+package libdns
+
+import "testing"
+
+func TestPunycode(t *testing.T) {
+	for i, test := range []struct {
+		unicode, ascii string
+	}{
+		{unicode: "", ascii: ""},
+		{unicode: "example.com.", ascii: "example.com."},
+		{unicode: "müller.de", ascii: "xn--mller-kva.de"},
+		{unicode: "www.müller.de.", ascii: "www.xn--mller-kva.de."},
+		{unicode: "münchen.example", ascii: "xn--mnchen-3ya.example"},
+		{unicode: "bücher.example.", ascii: "xn--bcher-kva.example."},
+		{unicode: "他们为什么不说中文", ascii: "xn--ihqwcrb4cv8a8dqg056pqjye"},
+		{unicode: "правительство.рф", ascii: "xn--80aealotwbjpid2k.xn--p1ai"},
+	} {
+		actualASCII, err := ToPunycode(test.unicode)
+		if err != nil {
+			t.Errorf("Test %d: ToPunycode(%q): expected no error but got: %v", i, test.unicode, err)
+		} else if actualASCII != test.ascii {
+			t.Errorf("Test %d: ToPunycode(%q): expected %q but got %q", i, test.unicode, test.ascii, actualASCII)
+		}
+
+		actualUnicode, err := FromPunycode(test.ascii)
+		if err != nil {
+			t.Errorf("Test %d: FromPunycode(%q): expected no error but got: %v", i, test.ascii, err)
+		} else if actualUnicode != test.unicode {
+			t.Errorf("Test %d: FromPunycode(%q): expected %q but got %q", i, test.ascii, test.unicode, actualUnicode)
+		}
+	}
+}
+
+func TestPunycodeCase(t *testing.T) {
+	actual, err := ToPunycode("Müller.DE")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if expect := "xn--mller-kva.DE"; actual != expect {
+		t.Errorf("Expected %q but got %q", expect, actual)
+	}
+	actual, err = FromPunycode("XN--MLLER-KVA.de")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if expect := "müller.de"; actual != expect {
+		t.Errorf("Expected %q but got %q", expect, actual)
+	}
+}
+
+func TestFromPunycodeErrors(t *testing.T) {
+	for i, input := range []string{
+		"xn--mller-kv!",
+		"xn--mller-kv",
+		"xn--ü-kva",
+	} {
+		if _, err := FromPunycode(input); err == nil {
+			t.Errorf("Test %d: expected error for %q but got none", i, input)
+		}
+	}
+}
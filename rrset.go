@@ -0,0 +1,191 @@
+package libdns
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RecordNames returns the sorted, deduplicated set of (relative) owner
+// names present in recs. Names are normalized as for GroupByRRSet: they
+// are lower-cased, and an empty name and "@" both refer to the zone
+// apex and are reported once as "@".
+func RecordNames(recs []Record) []string {
+	seen := make(map[string]struct{})
+	var names []string
+	for _, rec := range recs {
+		name := rrsetKey(rec).Name
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RRSetKey identifies an RRset: the set of records in a zone that
+// share the same owner name and type.
+type RRSetKey struct {
+	Name string
+	Type string
+}
+
+// rrsetKey returns the normalized RRSetKey of rec.
+func rrsetKey(rec Record) RRSetKey {
+	name := strings.ToLower(rec.Name)
+	if name == "" {
+		name = "@"
+	}
+	return RRSetKey{Name: name, Type: strings.ToUpper(rec.Type)}
+}
+
+// GroupByRRSet groups recs by RRset, which is the unit of change for
+// RecordSetter implementations. Within each group, records keep the
+// order in which they appear in the input.
+//
+// Keys are normalized so that equivalent names compare equal: names are
+// lower-cased (DNS names are case-insensitive), an empty name becomes
+// "@" (both denote the zone apex), and types are upper-cased. Names are
+// otherwise used as given; they are expected to be relative to the zone.
+func GroupByRRSet(recs []Record) map[RRSetKey][]Record {
+	groups := make(map[RRSetKey][]Record)
+	for _, rec := range recs {
+		key := rrsetKey(rec)
+		groups[key] = append(groups[key], rec)
+	}
+	return groups
+}
+
+// CheckRRSetConsistency returns an error if any records in recs which
+// belong to the same RRset disagree on their TTL. All records in an
+// RRset share a TTL (RFC 2181 section 5.2), so such input is ambiguous:
+// which TTL a provider applies is undefined. The error reports each
+// conflicting RRset along with the TTLs it was given.
+func CheckRRSetConsistency(recs []Record) error {
+	var keys []RRSetKey
+	ttls := make(map[RRSetKey][]time.Duration)
+	for _, rec := range recs {
+		key := rrsetKey(rec)
+		if _, ok := ttls[key]; !ok {
+			keys = append(keys, key)
+		}
+		seen := false
+		for _, ttl := range ttls[key] {
+			if ttl == rec.TTL {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			ttls[key] = append(ttls[key], rec.TTL)
+		}
+	}
+
+	var conflicts []string
+	for _, key := range keys {
+		if len(ttls[key]) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf("%s %s has TTLs %v", key.Name, key.Type, ttls[key]))
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("inconsistent RRsets: %s", strings.Join(conflicts, "; "))
+	}
+	return nil
+}
+
+// TypesAtName returns the sorted, distinct record types (upper-cased)
+// present among records at the (relative) name. Names are compared
+// case-insensitively, and an empty name and "@" both match the apex.
+func TypesAtName(records []Record, name string) []string {
+	name = rrsetKey(Record{Name: name}).Name
+
+	seen := make(map[string]struct{})
+	var types []string
+	for _, rec := range records {
+		key := rrsetKey(rec)
+		if key.Name != name {
+			continue
+		}
+		if _, ok := seen[key.Type]; ok {
+			continue
+		}
+		seen[key.Type] = struct{}{}
+		types = append(types, key.Type)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// SingleRRSet returns an error if records belong to more than one
+// RRset, for use by providers (or callers) that can only operate on
+// one RRset at a time.
+func SingleRRSet(records []Record) error {
+	for _, rec := range records {
+		if rrsetKey(rec) != rrsetKey(records[0]) {
+			return fmt.Errorf("records span multiple RRsets: %s %s and %s %s",
+				records[0].Name, records[0].Type, rec.Name, rec.Type)
+		}
+	}
+	return nil
+}
+
+// DuplicateSVCBPriorities returns the sorted, distinct priorities that
+// are shared by more than one SVCB or HTTPS record in the same RRset,
+// which makes the order of those records ambiguous. Callers may want to
+// warn about these.
+func DuplicateSVCBPriorities(records []Record) []uint {
+	type rrsetPriority struct {
+		key      RRSetKey
+		priority uint
+	}
+	counts := make(map[rrsetPriority]int)
+	seen := make(map[uint]struct{})
+	var dupes []uint
+	for _, rec := range records {
+		key := rrsetKey(rec)
+		if key.Type != "SVCB" && key.Type != "HTTPS" {
+			continue
+		}
+		rp := rrsetPriority{key, rec.Priority}
+		counts[rp]++
+		if counts[rp] < 2 {
+			continue
+		}
+		if _, ok := seen[rec.Priority]; ok {
+			continue
+		}
+		seen[rec.Priority] = struct{}{}
+		dupes = append(dupes, rec.Priority)
+	}
+	sort.Slice(dupes, func(i, j int) bool { return dupes[i] < dupes[j] })
+	return dupes
+}
+
+// NameGroup is the set of records at one name.
+type NameGroup struct {
+	Name    string
+	Records []Record
+}
+
+// GroupByName groups recs by name, for example to display them. Groups
+// are in the order each name first appears in recs, and records keep
+// their input order within each group. Names are normalized as for
+// GroupByRRSet: they are lower-cased, and an empty name becomes "@".
+func GroupByName(recs []Record) []NameGroup {
+	var groups []NameGroup
+	index := make(map[string]int)
+	for _, rec := range recs {
+		name := rrsetKey(rec).Name
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, NameGroup{Name: name})
+		}
+		groups[i].Records = append(groups[i].Records, rec)
+	}
+	return groups
+}
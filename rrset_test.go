@@ -0,0 +1,261 @@
+package libdns
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecordNames(t *testing.T) {
+	for i, test := range []struct {
+		recs   []Record
+		expect []string
+	}{
+		{
+			recs:   nil,
+			expect: nil,
+		},
+		{
+			recs: []Record{
+				{Type: "A", Name: "www", Value: "1.2.3.4"},
+				{Type: "AAAA", Name: "www", Value: "::1"},
+				{Type: "TXT", Name: "@", Value: "hello"},
+				{Type: "MX", Name: "", Value: "mail.example.com."},
+				{Type: "A", Name: "api", Value: "1.2.3.5"},
+			},
+			expect: []string{"@", "api", "www"},
+		},
+		{
+			recs: []Record{
+				{Type: "A", Name: "WWW", Value: "1.2.3.4"},
+				{Type: "A", Name: "www", Value: "1.2.3.5"},
+				{Type: "TXT", Name: "Api", Value: "hello"},
+			},
+			expect: []string{"api", "www"},
+		},
+	} {
+		actual := RecordNames(test.recs)
+		if !reflect.DeepEqual(actual, test.expect) {
+			t.Errorf("Test %d: expected %v but got %v", i, test.expect, actual)
+		}
+	}
+}
+
+func TestGroupByRRSet(t *testing.T) {
+	recs := []Record{
+		{Type: "A", Name: "www", Value: "1.2.3.4"},
+		{Type: "TXT", Name: "www", Value: "hello"},
+		{Type: "A", Name: "WWW", Value: "1.2.3.5"},
+		{Type: "aaaa", Name: "www", Value: "::1"},
+		{Type: "MX", Name: "", Value: "mail.example.com.", Priority: 10},
+		{Type: "MX", Name: "@", Value: "mail2.example.com.", Priority: 20},
+	}
+	expect := map[RRSetKey][]Record{
+		{Name: "www", Type: "A"}:    {recs[0], recs[2]},
+		{Name: "www", Type: "TXT"}:  {recs[1]},
+		{Name: "www", Type: "AAAA"}: {recs[3]},
+		{Name: "@", Type: "MX"}:     {recs[4], recs[5]},
+	}
+	actual := GroupByRRSet(recs)
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Expected %+v\nbut got  %+v", expect, actual)
+	}
+}
+
+func TestCheckRRSetConsistency(t *testing.T) {
+	for i, test := range []struct {
+		recs      []Record
+		shouldErr bool
+	}{
+		{
+			recs: nil,
+		},
+		{
+			recs: []Record{
+				{Type: "A", Name: "www", Value: "1.2.3.4", TTL: time.Minute},
+				{Type: "A", Name: "www", Value: "1.2.3.5", TTL: time.Minute},
+				{Type: "AAAA", Name: "www", Value: "::1", TTL: time.Hour},
+			},
+		},
+		{
+			recs: []Record{
+				{Type: "A", Name: "www", Value: "1.2.3.4", TTL: time.Minute},
+				{Type: "AAAA", Name: "www", Value: "::1", TTL: time.Hour},
+				{Type: "A", Name: "WWW", Value: "1.2.3.5", TTL: time.Hour},
+			},
+			shouldErr: true,
+		},
+	} {
+		err := CheckRRSetConsistency(test.recs)
+		if test.shouldErr && err == nil {
+			t.Errorf("Test %d: expected error but got none", i)
+		}
+		if !test.shouldErr && err != nil {
+			t.Errorf("Test %d: expected no error but got: %v", i, err)
+		}
+	}
+}
+
+func TestTypesAtName(t *testing.T) {
+	recs := []Record{
+		{Type: "TXT", Name: "www", Value: "hello"},
+		{Type: "A", Name: "www", Value: "1.2.3.4"},
+		{Type: "AAAA", Name: "WWW", Value: "::1"},
+		{Type: "A", Name: "www", Value: "1.2.3.5"},
+		{Type: "CNAME", Name: "api", Value: "www.example.com."},
+		{Type: "MX", Name: "", Value: "mail.example.com."},
+	}
+	for i, test := range []struct {
+		name   string
+		expect []string
+	}{
+		{name: "www", expect: []string{"A", "AAAA", "TXT"}},
+		{name: "api", expect: []string{"CNAME"}},
+		{name: "@", expect: []string{"MX"}},
+		{name: "nothing", expect: nil},
+	} {
+		actual := TypesAtName(recs, test.name)
+		if !reflect.DeepEqual(actual, test.expect) {
+			t.Errorf("Test %d: NAME=%s - expected %v but got %v", i, test.name, test.expect, actual)
+		}
+	}
+}
+
+func TestGroupByRRSetUnderscoreNames(t *testing.T) {
+	recs := []Record{
+		{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip2.example.com.", Priority: 20},
+		{Type: "SVCB", Name: "_dns.resolver", Value: "dns.example.com. alpn=dot"},
+		{Type: "SRV", Name: "_sip._udp", Value: "5060 sip.example.com.", Priority: 10},
+		{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip1.example.com.", Priority: 10},
+		{Type: "TXT", Name: "_sip._tcp", Value: "hello"},
+		{Type: "SVCB", Name: "_dns.resolver", Value: "dns2.example.com. alpn=h2"},
+	}
+	groups := GroupByRRSet(recs)
+	if len(groups) != 4 {
+		t.Errorf("Expected 4 RRsets but got %d: %+v", len(groups), groups)
+	}
+	for i, test := range []struct {
+		key    RRSetKey
+		expect []Record
+	}{
+		{key: RRSetKey{Name: "_sip._tcp", Type: "SRV"}, expect: []Record{recs[0], recs[3]}},
+		{key: RRSetKey{Name: "_sip._udp", Type: "SRV"}, expect: []Record{recs[2]}},
+		{key: RRSetKey{Name: "_sip._tcp", Type: "TXT"}, expect: []Record{recs[4]}},
+		{key: RRSetKey{Name: "_dns.resolver", Type: "SVCB"}, expect: []Record{recs[1], recs[5]}},
+	} {
+		if actual := groups[test.key]; !reflect.DeepEqual(actual, test.expect) {
+			t.Errorf("Test %d: %+v - expected %+v but got %+v", i, test.key, test.expect, actual)
+		}
+	}
+}
+
+func TestSingleRRSet(t *testing.T) {
+	for i, test := range []struct {
+		recs      []Record
+		shouldErr bool
+	}{
+		{
+			recs: nil,
+		},
+		{
+			recs: []Record{
+				{Type: "A", Name: "www", Value: "1.2.3.4"},
+				{Type: "A", Name: "WWW", Value: "1.2.3.5"},
+			},
+		},
+		{
+			recs: []Record{
+				{Type: "A", Name: "www", Value: "1.2.3.4"},
+				{Type: "AAAA", Name: "www", Value: "::1"},
+			},
+			shouldErr: true,
+		},
+		{
+			recs: []Record{
+				{Type: "A", Name: "@", Value: "1.2.3.4"},
+				{Type: "A", Name: "www", Value: "1.2.3.4"},
+			},
+			shouldErr: true,
+		},
+	} {
+		err := SingleRRSet(test.recs)
+		if test.shouldErr && err == nil {
+			t.Errorf("Test %d: expected error but got none", i)
+		}
+		if !test.shouldErr && err != nil {
+			t.Errorf("Test %d: expected no error but got: %v", i, err)
+		}
+	}
+}
+
+func TestDuplicateSVCBPriorities(t *testing.T) {
+	for i, test := range []struct {
+		recs   []Record
+		expect []uint
+	}{
+		{
+			recs: []Record{
+				{Type: "HTTPS", Name: "@", Priority: 1, Value: ". alpn=h2"},
+				{Type: "HTTPS", Name: "@", Priority: 2, Value: "alt.example.com."},
+			},
+			expect: nil,
+		},
+		{
+			recs: []Record{
+				{Type: "HTTPS", Name: "@", Priority: 2, Value: "a.example.com."},
+				{Type: "HTTPS", Name: "", Priority: 2, Value: "b.example.com."},
+				{Type: "SVCB", Name: "_dns", Priority: 1, Value: "dns1.example.com."},
+				{Type: "svcb", Name: "_DNS", Priority: 1, Value: "dns2.example.com."},
+				{Type: "SVCB", Name: "_dns", Priority: 1, Value: "dns3.example.com."},
+				{Type: "HTTPS", Name: "www", Priority: 3, Value: "a.example.com."},
+			},
+			expect: []uint{1, 2},
+		},
+		{
+			// same priority in different RRsets is fine
+			recs: []Record{
+				{Type: "HTTPS", Name: "@", Priority: 1, Value: "a.example.com."},
+				{Type: "HTTPS", Name: "www", Priority: 1, Value: "a.example.com."},
+				{Type: "SVCB", Name: "@", Priority: 1, Value: "a.example.com."},
+				{Type: "MX", Name: "@", Priority: 1, Value: "mx1.example.com."},
+				{Type: "MX", Name: "@", Priority: 1, Value: "mx2.example.com."},
+			},
+			expect: nil,
+		},
+	} {
+		actual := DuplicateSVCBPriorities(test.recs)
+		if !reflect.DeepEqual(actual, test.expect) {
+			t.Errorf("Test %d: expected %v but got %v", i, test.expect, actual)
+		}
+	}
+}
+
+func TestGroupByName(t *testing.T) {
+	recs := []Record{
+		{Type: "A", Name: "local", Value: "192.0.2.1"},
+		{Type: "A", Name: "*", Value: "192.0.2.2"},
+		{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10},
+		{Type: "AAAA", Name: "local", Value: "2001:db8::1"},
+		{Type: "A", Name: "www", Value: "192.0.2.3"},
+		{Type: "TXT", Name: "", Value: "hello"},
+		{Type: "TXT", Name: "*", Value: "wild"},
+		{Type: "CNAME", Name: "WWW.sub", Value: "www.example.com."},
+		{Type: "A", Name: "WWW", Value: "192.0.2.4"},
+	}
+	expect := []NameGroup{
+		{Name: "local", Records: []Record{recs[0], recs[3]}},
+		{Name: "*", Records: []Record{recs[1], recs[6]}},
+		{Name: "@", Records: []Record{recs[2], recs[5]}},
+		{Name: "www", Records: []Record{recs[4], recs[8]}},
+		{Name: "www.sub", Records: []Record{recs[7]}},
+	}
+
+	actual := GroupByName(recs)
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Expected:\n%+v\nbut got:\n%+v", expect, actual)
+	}
+
+	if groups := GroupByName(nil); groups != nil {
+		t.Errorf("Expected no groups but got %+v", groups)
+	}
+}
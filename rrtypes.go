@@ -0,0 +1,463 @@
+package libdns
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DNSKEY contains all the parsed data of a DNSKEY record.
+//
+// EXPERIMENTAL; subject to change or removal.
+type DNSKEY struct {
+	Name      string
+	Flags     uint16
+	Protocol  uint8
+	Algorithm uint8
+	PublicKey string // base64-encoded
+}
+
+// CDNSKEY contains all the parsed data of a CDNSKEY record, which has
+// the same layout as a DNSKEY record.
+//
+// EXPERIMENTAL; subject to change or removal.
+type CDNSKEY DNSKEY
+
+// ToDNSKEY parses the record into a DNSKEY struct with fully-parsed,
+// literal values.
+//
+// EXPERIMENTAL; subject to change or removal.
+func (r Record) ToDNSKEY() (DNSKEY, error) {
+	if r.Type != "DNSKEY" {
+		return DNSKEY{}, fmt.Errorf("record type not DNSKEY: %s", r.Type)
+	}
+	return parseDNSKEY(r)
+}
+
+// ToCDNSKEY parses the record into a CDNSKEY struct with fully-parsed,
+// literal values.
+//
+// EXPERIMENTAL; subject to change or removal.
+func (r Record) ToCDNSKEY() (CDNSKEY, error) {
+	if r.Type != "CDNSKEY" {
+		return CDNSKEY{}, fmt.Errorf("record type not CDNSKEY: %s", r.Type)
+	}
+	key, err := parseDNSKEY(r)
+	return CDNSKEY(key), err
+}
+
+func parseDNSKEY(r Record) (DNSKEY, error) {
+	// the public key may be split across several fields for readability
+	fields := strings.Fields(r.Value)
+	if len(fields) < 4 {
+		return DNSKEY{}, fmt.Errorf("malformed %s value; expected: '<flags> <protocol> <algorithm> <public key>'", r.Type)
+	}
+
+	flags, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return DNSKEY{}, fmt.Errorf("invalid flags %s: %v", fields[0], err)
+	}
+	protocol, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil {
+		return DNSKEY{}, fmt.Errorf("invalid protocol %s: %v", fields[1], err)
+	}
+	algorithm, err := strconv.ParseUint(fields[2], 10, 8)
+	if err != nil {
+		return DNSKEY{}, fmt.Errorf("invalid algorithm %s: %v", fields[2], err)
+	}
+
+	return DNSKEY{
+		Name:      r.Name,
+		Flags:     uint16(flags),
+		Protocol:  uint8(protocol),
+		Algorithm: uint8(algorithm),
+		PublicKey: strings.Join(fields[3:], ""),
+	}, nil
+}
+
+// ToRecord converts the parsed DNSKEY data to a Record struct.
+//
+// EXPERIMENTAL; subject to change or removal.
+func (k DNSKEY) ToRecord() Record {
+	return Record{
+		Type:  "DNSKEY",
+		Name:  k.Name,
+		Value: fmt.Sprintf("%d %d %d %s", k.Flags, k.Protocol, k.Algorithm, k.PublicKey),
+	}
+}
+
+// ToRecord converts the parsed CDNSKEY data to a Record struct.
+//
+// EXPERIMENTAL; subject to change or removal.
+func (k CDNSKEY) ToRecord() Record {
+	rec := DNSKEY(k).ToRecord()
+	rec.Type = "CDNSKEY"
+	return rec
+}
+
+// MX contains all the parsed data of an MX record.
+//
+// EXPERIMENTAL; subject to change or removal.
+type MX struct {
+	Name       string
+	Preference uint
+	Target     string
+}
+
+// ToMX parses the record into a MX struct with fully-parsed, literal values.
+//
+// EXPERIMENTAL; subject to change or removal.
+func (r Record) ToMX() (MX, error) {
+	if r.Type != "MX" {
+		return MX{}, fmt.Errorf("record type not MX: %s", r.Type)
+	}
+
+	target := strings.TrimSpace(r.Value)
+	if target == "" || strings.ContainsAny(target, " \t") {
+		return MX{}, fmt.Errorf("malformed MX value; expected: '<target>'")
+	}
+
+	return MX{
+		Name:       r.Name,
+		Preference: r.Priority,
+		Target:     target,
+	}, nil
+}
+
+// ToRecord converts the parsed MX data to a Record struct.
+//
+// EXPERIMENTAL; subject to change or removal.
+func (m MX) ToRecord() Record {
+	return Record{
+		Type:     "MX",
+		Name:     m.Name,
+		Priority: m.Preference,
+		Value:    m.Target,
+	}
+}
+
+// MXEqual reports whether a and b have the same preference and target.
+// Targets are compared case-insensitively and regardless of a trailing
+// dot, since providers format them inconsistently. Names are not
+// compared.
+func MXEqual(a, b MX) bool {
+	return a.Preference == b.Preference && namesEqual(a.Target, b.Target)
+}
+
+// Address contains all the parsed data of an A or AAAA record.
+//
+// EXPERIMENTAL; subject to change or removal.
+type Address struct {
+	Name string
+	IP   netip.Addr
+}
+
+// ToAddress parses the record into an Address struct with fully-parsed,
+// literal values. The address must be of the family that matches the
+// record type: IPv4 for A records, and IPv6 for AAAA records.
+//
+// EXPERIMENTAL; subject to change or removal.
+func (r Record) ToAddress() (Address, error) {
+	if r.Type != "A" && r.Type != "AAAA" {
+		return Address{}, fmt.Errorf("record type not A or AAAA: %s", r.Type)
+	}
+
+	ip, err := netip.ParseAddr(strings.TrimSpace(r.Value))
+	if err != nil {
+		return Address{}, fmt.Errorf("invalid IP address %s: %v", r.Value, err)
+	}
+	if r.Type == "A" && !ip.Is4() {
+		return Address{}, fmt.Errorf("A record has IPv6 address: %s", ip)
+	}
+	if r.Type == "AAAA" && !ip.Is6() {
+		return Address{}, fmt.Errorf("AAAA record has IPv4 address: %s", ip)
+	}
+
+	return Address{
+		Name: r.Name,
+		IP:   ip,
+	}, nil
+}
+
+// IsIPv6 reports whether a is an IPv6 address, which is the data of an
+// AAAA record, rather than an IPv4 address of an A record.
+func (a Address) IsIPv6() bool {
+	return a.IP.Is6()
+}
+
+// ToRecord converts the parsed address data to a Record struct, whose
+// type is A or AAAA depending on the address family.
+//
+// EXPERIMENTAL; subject to change or removal.
+func (a Address) ToRecord() Record {
+	typ := "A"
+	if a.IsIPv6() {
+		typ = "AAAA"
+	}
+	return Record{
+		Type:  typ,
+		Name:  a.Name,
+		Value: a.IP.String(),
+	}
+}
+
+// CAA contains all the parsed data of a CAA record.
+//
+// EXPERIMENTAL; subject to change or removal.
+type CAA struct {
+	Name  string
+	Flags uint8
+	Tag   string
+	Value string // raw value after the tag, unquoted
+}
+
+// ToCAA parses the record into a CAA struct with fully-parsed, literal
+// values. The value is unquoted, but otherwise kept as it is; for the
+// "issue" and "issuewild" tags, use IssueParameters to parse it.
+//
+// EXPERIMENTAL; subject to change or removal.
+func (r Record) ToCAA() (CAA, error) {
+	if r.Type != "CAA" {
+		return CAA{}, fmt.Errorf("record type not CAA: %s", r.Type)
+	}
+
+	flagsField, tag, valueField, ok := splitCAA(r.Value)
+	if !ok {
+		return CAA{}, fmt.Errorf("malformed CAA value; expected: '<flags> <tag> <value>'")
+	}
+	flags, err := strconv.ParseUint(flagsField, 10, 8)
+	if err != nil {
+		return CAA{}, fmt.Errorf("invalid flags %s: %v", flagsField, err)
+	}
+	if !isAlphanumeric(tag) {
+		return CAA{}, fmt.Errorf("invalid CAA tag: %s", tag)
+	}
+	value, err := unquoteCharacterString(valueField)
+	if err != nil {
+		return CAA{}, fmt.Errorf("invalid CAA value %s: %v", valueField, err)
+	}
+
+	return CAA{
+		Name:  r.Name,
+		Flags: uint8(flags),
+		Tag:   tag,
+		Value: value,
+	}, nil
+}
+
+// splitCAA splits the data of a CAA record into its flags, tag and
+// value, which may be separated by any amount of whitespace. The value
+// is the rest of the data, as written (i.e. possibly quoted). It
+// returns false if any of them is missing.
+func splitCAA(data string) (flags, tag, value string, ok bool) {
+	flags, value = cutField(strings.TrimSpace(data))
+	tag, value = cutField(value)
+	return flags, tag, value, flags != "" && tag != "" && value != ""
+}
+
+// unquoteCAAValue returns the value of a CAA record, as returned by
+// splitCAA, without its quotes (if any) and with escape sequences
+// resolved, and true. If value is not a valid character-string, it is
+// returned unchanged with false.
+func unquoteCAAValue(value string) (string, bool) {
+	unquoted, err := unquoteCharacterString(value)
+	if err != nil {
+		return value, false
+	}
+	return unquoted, true
+}
+
+// cutField returns the first whitespace-separated field of s, and the
+// rest of s after the whitespace following it.
+func cutField(s string) (field, rest string) {
+	i := strings.IndexFunc(s, unicode.IsSpace)
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], strings.TrimLeftFunc(s[i:], unicode.IsSpace)
+}
+
+// ToRecord converts the parsed CAA data to a Record struct.
+//
+// EXPERIMENTAL; subject to change or removal.
+func (c CAA) ToRecord() Record {
+	return Record{
+		Type:  "CAA",
+		Name:  c.Name,
+		Value: fmt.Sprintf("%d %s %s", c.Flags, c.Tag, quoteCharacterString(c.Value)),
+	}
+}
+
+// IssueParameters parses the value of an "issue" or "issuewild" CAA
+// record (RFC 8659 section 4.2) into the domain name of the authorized
+// CA and its parameters, such as "validationmethods" or "accounturi"
+// (RFC 8657). The domain is empty if the record forbids issuance, as
+// with a value of ";". Parameter values are returned as written.
+//
+// EXPERIMENTAL; subject to change or removal.
+func (c CAA) IssueParameters() (domain string, params map[string]string, err error) {
+	if tag := strings.ToLower(c.Tag); tag != "issue" && tag != "issuewild" {
+		return "", nil, fmt.Errorf("CAA tag not issue or issuewild: %s", c.Tag)
+	}
+
+	parts := strings.Split(c.Value, ";")
+	domain = strings.TrimSpace(parts[0])
+	params = make(map[string]string)
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		eq := strings.Index(part, "=")
+		if eq < 0 {
+			return "", nil, fmt.Errorf("malformed CAA parameter %s; expected: '<tag>=<value>'", part)
+		}
+		key := strings.TrimSpace(part[:eq])
+		if !isAlphanumeric(key) {
+			return "", nil, fmt.Errorf("invalid CAA parameter tag: %s", key)
+		}
+		params[key] = strings.TrimSpace(part[eq+1:])
+	}
+	return domain, params, nil
+}
+
+// SOA contains all the parsed data of an SOA record. The timer values
+// are durations, which are written in whole seconds.
+//
+// EXPERIMENTAL; subject to change or removal.
+type SOA struct {
+	Name    string
+	TTL     time.Duration
+	MName   string // primary name server
+	RName   string // mailbox of the person responsible, as a domain name
+	Serial  uint32
+	Refresh time.Duration
+	Retry   time.Duration
+	Expire  time.Duration
+	Minimum time.Duration // TTL for negative caching (RFC 2308)
+}
+
+// Default SOA timer values, as recommended by RIPE-203.
+const (
+	defaultSOARefresh = 24 * time.Hour
+	defaultSOARetry   = 2 * time.Hour
+	defaultSOAExpire  = 1000 * time.Hour
+	defaultSOAMinimum = time.Hour
+)
+
+// DefaultSOA returns an SOA record for the apex of a new zone whose
+// primary name server is primaryNS, with conventional timer values and
+// a date-based serial number of the form YYYYMMDDnn for the current
+// date (UTC) with nn = 01. The mailbox may be given as an email
+// address, such as "hostmaster@example.com", or in the domain name form
+// used by SOA records, such as "hostmaster.example.com.".
+//
+// EXPERIMENTAL; subject to change or removal.
+func DefaultSOA(primaryNS, mailbox string, ttl time.Duration) SOA {
+	return SOA{
+		Name:    "@",
+		TTL:     ttl,
+		MName:   absoluteTarget(primaryNS),
+		RName:   mailboxToRName(mailbox),
+		Serial:  dateSerial(time.Now()),
+		Refresh: defaultSOARefresh,
+		Retry:   defaultSOARetry,
+		Expire:  defaultSOAExpire,
+		Minimum: defaultSOAMinimum,
+	}
+}
+
+// dateSerial returns the first serial number of the form YYYYMMDDnn
+// for the date of t in UTC.
+func dateSerial(t time.Time) uint32 {
+	t = t.UTC()
+	return uint32(t.Year())*1000000 + uint32(t.Month())*10000 + uint32(t.Day())*100 + 1
+}
+
+// mailboxToRName converts an email address to the domain name form of
+// an SOA RNAME (RFC 1035 section 8), escaping dots in the local part.
+// Mailboxes already in that form are only made absolute.
+func mailboxToRName(mailbox string) string {
+	at := strings.LastIndex(mailbox, "@")
+	if at < 0 {
+		return absoluteTarget(mailbox)
+	}
+	local := strings.ReplaceAll(mailbox[:at], ".", `\.`)
+	return absoluteTarget(local + "." + mailbox[at+1:])
+}
+
+// ToSOA parses the record into a SOA struct with fully-parsed, literal
+// values.
+//
+// EXPERIMENTAL; subject to change or removal.
+func (r Record) ToSOA() (SOA, error) {
+	if r.Type != "SOA" {
+		return SOA{}, fmt.Errorf("record type not SOA: %s", r.Type)
+	}
+
+	fields := strings.Fields(r.Value)
+	if len(fields) != 7 {
+		return SOA{}, fmt.Errorf("malformed SOA value; expected: '<mname> <rname> <serial> <refresh> <retry> <expire> <minimum>'")
+	}
+
+	var nums [5]uint64
+	for i, name := range []string{"serial", "refresh", "retry", "expire", "minimum"} {
+		n, err := strconv.ParseUint(fields[i+2], 10, 32)
+		if err != nil {
+			return SOA{}, fmt.Errorf("invalid %s %s: %v", name, fields[i+2], err)
+		}
+		nums[i] = n
+	}
+
+	return SOA{
+		Name:    r.Name,
+		TTL:     r.TTL,
+		MName:   fields[0],
+		RName:   fields[1],
+		Serial:  uint32(nums[0]),
+		Refresh: time.Duration(nums[1]) * time.Second,
+		Retry:   time.Duration(nums[2]) * time.Second,
+		Expire:  time.Duration(nums[3]) * time.Second,
+		Minimum: time.Duration(nums[4]) * time.Second,
+	}, nil
+}
+
+// ToRecord converts the parsed SOA data to a Record struct.
+//
+// EXPERIMENTAL; subject to change or removal.
+func (s SOA) ToRecord() Record {
+	return Record{
+		Type: "SOA",
+		Name: s.Name,
+		TTL:  s.TTL,
+		Value: fmt.Sprintf("%s %s %d %d %d %d %d", s.MName, s.RName, s.Serial,
+			int64(s.Refresh.Seconds()), int64(s.Retry.Seconds()),
+			int64(s.Expire.Seconds()), int64(s.Minimum.Seconds())),
+	}
+}
+
+// IsSOA reports whether r is an SOA record. As with ToSOA and the other
+// typed accessors, the type must be given in upper case.
+func IsSOA(r Record) bool {
+	return r.Type == "SOA"
+}
+
+// FindSOA returns the first SOA record among records that can be
+// parsed, and true; or false if there is none. Not all providers return
+// the SOA record from GetRecords.
+//
+// EXPERIMENTAL; subject to change or removal.
+func FindSOA(records []Record) (SOA, bool) {
+	for _, rec := range records {
+		if !IsSOA(rec) {
+			continue
+		}
+		if soa, err := rec.ToSOA(); err == nil {
+			return soa, true
+		}
+	}
+	return SOA{}, false
+}
@@ -0,0 +1,405 @@
+package libdns
+
+import (
+	"fmt"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDNSKEYRecords(t *testing.T) {
+	for i, test := range []struct {
+		rec    Record
+		dnskey DNSKEY
+	}{
+		{
+			rec: Record{
+				Type:  "DNSKEY",
+				Name:  "@",
+				Value: "257 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ==",
+			},
+			dnskey: DNSKEY{
+				Name:      "@",
+				Flags:     257,
+				Protocol:  3,
+				Algorithm: 13,
+				PublicKey: "mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ==",
+			},
+		},
+		{
+			rec: Record{
+				Type:  "DNSKEY",
+				Name:  "sub",
+				Value: "256 3 8 AwEAAag=",
+			},
+			dnskey: DNSKEY{
+				Name:      "sub",
+				Flags:     256,
+				Protocol:  3,
+				Algorithm: 8,
+				PublicKey: "AwEAAag=",
+			},
+		},
+	} {
+		// Record -> DNSKEY
+		actualKey, err := test.rec.ToDNSKEY()
+		if err != nil {
+			t.Errorf("Test %d: Record -> DNSKEY: Expected no error, but got: %v", i, err)
+			continue
+		}
+		if actualKey != test.dnskey {
+			t.Errorf("Test %d: Record -> DNSKEY: For record %+v:\nEXPECTED %+v\nGOT      %+v",
+				i, test.rec, test.dnskey, actualKey)
+		}
+
+		// DNSKEY -> Record
+		actualRec := test.dnskey.ToRecord()
+		if actualRec != test.rec {
+			t.Errorf("Test %d: DNSKEY -> Record: For DNSKEY %+v:\nEXPECTED %+v\nGOT      %+v",
+				i, test.dnskey, test.rec, actualRec)
+		}
+
+		// CDNSKEY round trip
+		cdnskeyRec := CDNSKEY(test.dnskey).ToRecord()
+		if cdnskeyRec.Type != "CDNSKEY" {
+			t.Errorf("Test %d: CDNSKEY -> Record: expected type CDNSKEY but got %s", i, cdnskeyRec.Type)
+		}
+		actualCDNSKEY, err := cdnskeyRec.ToCDNSKEY()
+		if err != nil {
+			t.Errorf("Test %d: Record -> CDNSKEY: Expected no error, but got: %v", i, err)
+			continue
+		}
+		if DNSKEY(actualCDNSKEY) != test.dnskey {
+			t.Errorf("Test %d: Record -> CDNSKEY:\nEXPECTED %+v\nGOT      %+v", i, test.dnskey, actualCDNSKEY)
+		}
+	}
+}
+
+func TestDNSKEYErrors(t *testing.T) {
+	for i, rec := range []Record{
+		{Type: "TXT", Value: "257 3 13 abc="},
+		{Type: "DNSKEY", Value: "257 3 13"},
+		{Type: "DNSKEY", Value: "65536 3 13 abc="},
+		{Type: "DNSKEY", Value: "257 x 13 abc="},
+		{Type: "DNSKEY", Value: "257 3 256 abc="},
+	} {
+		if _, err := rec.ToDNSKEY(); err == nil {
+			t.Errorf("Test %d: expected error for record %+v but got none", i, rec)
+		}
+	}
+}
+
+func TestMXRecords(t *testing.T) {
+	rec := Record{Type: "MX", Name: "@", Priority: 10, Value: "mail.example.com."}
+	mx := MX{Name: "@", Preference: 10, Target: "mail.example.com."}
+
+	actualMX, err := rec.ToMX()
+	if err != nil {
+		t.Fatalf("Record -> MX: Expected no error, but got: %v", err)
+	}
+	if actualMX != mx {
+		t.Errorf("Record -> MX:\nEXPECTED %+v\nGOT      %+v", mx, actualMX)
+	}
+	if actualRec := mx.ToRecord(); actualRec != rec {
+		t.Errorf("MX -> Record:\nEXPECTED %+v\nGOT      %+v", rec, actualRec)
+	}
+
+	for i, bad := range []Record{
+		{Type: "A", Value: "mail.example.com."},
+		{Type: "MX", Value: ""},
+		{Type: "MX", Value: "10 mail.example.com."},
+	} {
+		if _, err := bad.ToMX(); err == nil {
+			t.Errorf("Test %d: expected error for record %+v but got none", i, bad)
+		}
+	}
+}
+
+func TestMXEqual(t *testing.T) {
+	for i, test := range []struct {
+		a, b   MX
+		expect bool
+	}{
+		{
+			a:      MX{Preference: 10, Target: "mail.example.com."},
+			b:      MX{Preference: 10, Target: "mail.example.com."},
+			expect: true,
+		},
+		{
+			a:      MX{Preference: 10, Target: "mail.example.com."},
+			b:      MX{Preference: 10, Target: "mail2.example.com."},
+			expect: false,
+		},
+		{
+			a:      MX{Preference: 10, Target: "mail.example.com."},
+			b:      MX{Preference: 20, Target: "mail.example.com."},
+			expect: false,
+		},
+		{
+			a:      MX{Preference: 10, Target: "mail.example.com."},
+			b:      MX{Preference: 10, Target: "Mail.Example.com"},
+			expect: true,
+		},
+	} {
+		actual := MXEqual(test.a, test.b)
+		if actual != test.expect {
+			t.Errorf("Test %d: A=%+v B=%+v - expected %t but got %t", i, test.a, test.b, test.expect, actual)
+		}
+	}
+}
+
+func TestAddressRecords(t *testing.T) {
+	for i, test := range []struct {
+		rec    Record
+		addr   Address
+		isIPv6 bool
+	}{
+		{
+			rec:  Record{Type: "A", Name: "@", Value: "192.0.2.1"},
+			addr: Address{Name: "@", IP: netip.MustParseAddr("192.0.2.1")},
+		},
+		{
+			rec:    Record{Type: "AAAA", Name: "www", Value: "2001:db8::1"},
+			addr:   Address{Name: "www", IP: netip.MustParseAddr("2001:db8::1")},
+			isIPv6: true,
+		},
+	} {
+		actualAddr, err := test.rec.ToAddress()
+		if err != nil {
+			t.Errorf("Test %d: Record -> Address: Expected no error, but got: %v", i, err)
+			continue
+		}
+		if actualAddr != test.addr {
+			t.Errorf("Test %d: Record -> Address: For record %+v:\nEXPECTED %+v\nGOT      %+v",
+				i, test.rec, test.addr, actualAddr)
+		}
+		if actualAddr.IsIPv6() != test.isIPv6 {
+			t.Errorf("Test %d: Expected IsIPv6() to be %t", i, test.isIPv6)
+		}
+		if actualRec := test.addr.ToRecord(); actualRec != test.rec {
+			t.Errorf("Test %d: Address -> Record: For address %+v:\nEXPECTED %+v\nGOT      %+v",
+				i, test.addr, test.rec, actualRec)
+		}
+	}
+
+	for i, bad := range []Record{
+		{Type: "MX", Value: "192.0.2.1"},
+		{Type: "A", Value: "not-an-ip"},
+		{Type: "A", Value: "2001:db8::1"},
+		{Type: "A", Value: "::ffff:192.0.2.1"},
+		{Type: "AAAA", Value: "192.0.2.1"},
+	} {
+		if _, err := bad.ToAddress(); err == nil {
+			t.Errorf("Test %d: expected error for record %+v but got none", i, bad)
+		}
+	}
+}
+
+func TestCAARecords(t *testing.T) {
+	rec := Record{Type: "CAA", Name: "@", Value: `0 issue "letsencrypt.org; validationmethods=dns-01; accounturi=https://acme-v02.api.letsencrypt.org/acme/acct/1234"`}
+	caa := CAA{
+		Name:  "@",
+		Flags: 0,
+		Tag:   "issue",
+		Value: "letsencrypt.org; validationmethods=dns-01; accounturi=https://acme-v02.api.letsencrypt.org/acme/acct/1234",
+	}
+
+	actualCAA, err := rec.ToCAA()
+	if err != nil {
+		t.Fatalf("Record -> CAA: Expected no error, but got: %v", err)
+	}
+	if actualCAA != caa {
+		t.Errorf("Record -> CAA:\nEXPECTED %+v\nGOT      %+v", caa, actualCAA)
+	}
+	if actualRec := caa.ToRecord(); actualRec != rec {
+		t.Errorf("CAA -> Record:\nEXPECTED %+v\nGOT      %+v", rec, actualRec)
+	}
+
+	// fields may be separated by any whitespace
+	spaced := Record{Type: "CAA", Name: "@", Value: "0  issue\t\"letsencrypt.org\""}
+	expect := CAA{Name: "@", Flags: 0, Tag: "issue", Value: "letsencrypt.org"}
+	if actualCAA, err := spaced.ToCAA(); err != nil || actualCAA != expect {
+		t.Errorf("Record -> CAA: For record %+v: expected %+v but got %+v (err=%v)", spaced, expect, actualCAA, err)
+	}
+
+	for i, bad := range []Record{
+		{Type: "TXT", Value: `0 issue "letsencrypt.org"`},
+		{Type: "CAA", Value: "0 issue"},
+		{Type: "CAA", Value: `256 issue "letsencrypt.org"`},
+		{Type: "CAA", Value: `0 is-sue "letsencrypt.org"`},
+	} {
+		if _, err := bad.ToCAA(); err == nil {
+			t.Errorf("Test %d: expected error for record %+v but got none", i, bad)
+		}
+	}
+}
+
+func TestCAAIssueParameters(t *testing.T) {
+	for i, test := range []struct {
+		caa       CAA
+		domain    string
+		params    map[string]string
+		shouldErr bool
+	}{
+		{
+			caa:    CAA{Tag: "issue", Value: "letsencrypt.org; validationmethods=dns-01; accounturi=https://acme-v02.api.letsencrypt.org/acme/acct/1234"},
+			domain: "letsencrypt.org",
+			params: map[string]string{
+				"validationmethods": "dns-01",
+				"accounturi":        "https://acme-v02.api.letsencrypt.org/acme/acct/1234",
+			},
+		},
+		{
+			caa:    CAA{Tag: "issuewild", Value: "letsencrypt.org"},
+			domain: "letsencrypt.org",
+			params: map[string]string{},
+		},
+		{
+			caa:    CAA{Tag: "issue", Value: ";"},
+			domain: "",
+			params: map[string]string{},
+		},
+		{
+			caa:       CAA{Tag: "iodef", Value: "mailto:security@example.com"},
+			shouldErr: true,
+		},
+		{
+			caa:       CAA{Tag: "issue", Value: "letsencrypt.org; validationmethods"},
+			shouldErr: true,
+		},
+	} {
+		domain, params, err := test.caa.IssueParameters()
+		if test.shouldErr {
+			if err == nil {
+				t.Errorf("Test %d: expected error for %+v but got none", i, test.caa)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: Expected no error, but got: %v", i, err)
+			continue
+		}
+		if domain != test.domain {
+			t.Errorf("Test %d: Expected domain %q but got %q", i, test.domain, domain)
+		}
+		if !reflect.DeepEqual(params, test.params) {
+			t.Errorf("Test %d: Expected parameters %v but got %v", i, test.params, params)
+		}
+	}
+}
+
+func TestSOARecords(t *testing.T) {
+	rec := Record{
+		Type:  "SOA",
+		Name:  "@",
+		TTL:   time.Hour,
+		Value: "ns1.example.com. hostmaster.example.com. 2024010101 86400 7200 3600000 3600",
+	}
+	soa := SOA{
+		Name:    "@",
+		TTL:     time.Hour,
+		MName:   "ns1.example.com.",
+		RName:   "hostmaster.example.com.",
+		Serial:  2024010101,
+		Refresh: 24 * time.Hour,
+		Retry:   2 * time.Hour,
+		Expire:  1000 * time.Hour,
+		Minimum: time.Hour,
+	}
+
+	actualSOA, err := rec.ToSOA()
+	if err != nil {
+		t.Fatalf("Record -> SOA: Expected no error, but got: %v", err)
+	}
+	if actualSOA != soa {
+		t.Errorf("Record -> SOA:\nEXPECTED %+v\nGOT      %+v", soa, actualSOA)
+	}
+	if actualRec := soa.ToRecord(); actualRec != rec {
+		t.Errorf("SOA -> Record:\nEXPECTED %+v\nGOT      %+v", rec, actualRec)
+	}
+
+	for i, bad := range []Record{
+		{Type: "NS", Value: rec.Value},
+		{Type: "SOA", Value: "ns1.example.com. hostmaster.example.com. 1 2 3 4"},
+		{Type: "SOA", Value: "ns1.example.com. hostmaster.example.com. 4294967296 2 3 4 5"},
+		{Type: "SOA", Value: "ns1.example.com. hostmaster.example.com. 1 2 3 -4 5"},
+	} {
+		if _, err := bad.ToSOA(); err == nil {
+			t.Errorf("Test %d: expected error for record %+v but got none", i, bad)
+		}
+	}
+}
+
+func TestDefaultSOA(t *testing.T) {
+	before := time.Now().UTC()
+	soa := DefaultSOA("ns1.example.com", "host.master@example.com", 2*time.Hour)
+	after := time.Now().UTC()
+
+	expect := SOA{
+		Name:    "@",
+		TTL:     2 * time.Hour,
+		MName:   "ns1.example.com.",
+		RName:   `host\.master.example.com.`,
+		Refresh: 24 * time.Hour,
+		Retry:   2 * time.Hour,
+		Expire:  1000 * time.Hour,
+		Minimum: time.Hour,
+	}
+	actual := soa
+	actual.Serial = 0
+	if actual != expect {
+		t.Errorf("EXPECTED %+v\nGOT      %+v", expect, actual)
+	}
+
+	// the serial is YYYYMMDD01 for today (allowing for midnight passing)
+	serial := fmt.Sprint(soa.Serial)
+	if serial != before.Format("20060102")+"01" && serial != after.Format("20060102")+"01" {
+		t.Errorf("Expected date-based serial for %s but got %s", before.Format("2006-01-02"), serial)
+	}
+
+	if rname := DefaultSOA("ns1.example.com.", "hostmaster.example.com.", 0).RName; rname != "hostmaster.example.com." {
+		t.Errorf("Expected RNAME in domain name form to be kept but got %s", rname)
+	}
+
+	if _, err := soa.ToRecord().ToSOA(); err != nil {
+		t.Errorf("Expected default SOA to round-trip but got: %v", err)
+	}
+}
+
+func TestFindSOA(t *testing.T) {
+	soaRec := Record{
+		Type:  "SOA",
+		Name:  "@",
+		TTL:   time.Hour,
+		Value: "ns1.example.com. hostmaster.example.com. 2024010101 86400 7200 3600000 3600",
+	}
+	zone := []Record{
+		{Type: "NS", Name: "@", Value: "ns1.example.com."},
+		{Type: "SOA", Name: "@", Value: "malformed"},
+		soaRec,
+		{Type: "A", Name: "www", Value: "192.0.2.1"},
+	}
+
+	if !IsSOA(soaRec) {
+		t.Errorf("Expected %+v to be an SOA record", soaRec)
+	}
+	if IsSOA(zone[0]) {
+		t.Errorf("Expected %+v not to be an SOA record", zone[0])
+	}
+	if lower := (Record{Type: "soa", Value: soaRec.Value}); IsSOA(lower) {
+		t.Errorf("Expected %+v with lower-case type not to be an SOA record, as for ToSOA", lower)
+	}
+
+	soa, ok := FindSOA(zone)
+	if !ok {
+		t.Fatalf("Expected to find SOA record")
+	}
+	if soa.MName != "ns1.example.com." || soa.Serial != 2024010101 || soa.TTL != time.Hour {
+		t.Errorf("Expected SOA from %+v but got %+v", soaRec, soa)
+	}
+
+	if soa, ok := FindSOA(append(zone[:1:1], zone[3])); ok {
+		t.Errorf("Expected no SOA record but got %+v", soa)
+	}
+}
@@ -0,0 +1,64 @@
+package libdns
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Summarize returns a short, human-readable summary of recs giving the
+// number of records of each type, sorted by type, and the total; for
+// example: "A: 3, MX: 1, TXT: 2 (6 total)".
+func Summarize(recs []Record) string {
+	if len(recs) == 0 {
+		return "0 total"
+	}
+
+	counts := make(map[string]int)
+	for _, rec := range recs {
+		counts[strings.ToUpper(rec.Type)]++
+	}
+	types := make([]string, 0, len(counts))
+	for typ := range counts {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+
+	parts := make([]string, len(types))
+	for i, typ := range types {
+		parts[i] = fmt.Sprintf("%s: %d", typ, counts[typ])
+	}
+	return fmt.Sprintf("%s (%d total)", strings.Join(parts, ", "), len(recs))
+}
+
+// SummarizeChanges returns a human-readable summary of batch, suitable
+// for a confirmation prompt before applying it. See SummarizeDiff.
+func SummarizeChanges(batch ChangeBatch) string {
+	return SummarizeDiff(batch.Append, batch.Set, batch.Delete)
+}
+
+// SummarizeDiff returns a human-readable summary of records to create,
+// update and delete: a line with the number of each, for example
+// "3 to add, 1 to update, 2 to delete", followed by one line per
+// record prefixed with "+", "~" or "-" respectively.
+func SummarizeDiff(create, update, remove []Record) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d to add, %d to update, %d to delete", len(create), len(update), len(remove))
+	for _, group := range []struct {
+		prefix string
+		recs   []Record
+	}{
+		{"+", create},
+		{"~", update},
+		{"-", remove},
+	} {
+		for _, rec := range group.recs {
+			name := rec.Name
+			if name == "" {
+				name = "@"
+			}
+			fmt.Fprintf(&sb, "\n  %s %s %s %s", group.prefix, name, strings.ToUpper(rec.Type), recordData(rec))
+		}
+	}
+	return sb.String()
+}
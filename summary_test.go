@@ -0,0 +1,62 @@
+package libdns
+
+import "testing"
+
+func TestSummarize(t *testing.T) {
+	for i, test := range []struct {
+		recs   []Record
+		expect string
+	}{
+		{
+			recs:   nil,
+			expect: "0 total",
+		},
+		{
+			recs: []Record{
+				{Type: "TXT", Name: "@", Value: "a"},
+				{Type: "A", Name: "www", Value: "1.2.3.4"},
+				{Type: "MX", Name: "@", Value: "mail.example.com."},
+				{Type: "a", Name: "api", Value: "1.2.3.5"},
+				{Type: "TXT", Name: "www", Value: "b"},
+				{Type: "A", Name: "@", Value: "1.2.3.6"},
+			},
+			expect: "A: 3, MX: 1, TXT: 2 (6 total)",
+		},
+	} {
+		actual := Summarize(test.recs)
+		if actual != test.expect {
+			t.Errorf("Test %d: expected %q but got %q", i, test.expect, actual)
+		}
+	}
+}
+
+func TestSummarizeChanges(t *testing.T) {
+	batch := ChangeBatch{
+		Append: []Record{
+			{Type: "A", Name: "www", Value: "192.0.2.1"},
+			{Type: "AAAA", Name: "www", Value: "2001:db8::1"},
+			{Type: "TXT", Name: "", Value: "hello"},
+		},
+		Set: []Record{
+			{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10},
+		},
+		Delete: []Record{
+			{Type: "CNAME", Name: "old", Value: "example.com."},
+			{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 10, Weight: 5},
+		},
+	}
+	expect := `3 to add, 1 to update, 2 to delete
+  + www A 192.0.2.1
+  + www AAAA 2001:db8::1
+  + @ TXT "hello"
+  ~ @ MX 10 mail.example.com.
+  - old CNAME example.com.
+  - _sip._tcp SRV 10 5 5060 sip.example.com.`
+
+	if actual := SummarizeChanges(batch); actual != expect {
+		t.Errorf("Expected:\n%s\nbut got:\n%s", expect, actual)
+	}
+	if actual, expect := SummarizeDiff(nil, nil, nil), "0 to add, 0 to update, 0 to delete"; actual != expect {
+		t.Errorf("Expected %q but got %q", expect, actual)
+	}
+}
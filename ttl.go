@@ -0,0 +1,121 @@
+package libdns
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TTLParser parses a TTL as it is represented by a provider's API.
+type TTLParser interface {
+	// ParseTTL returns the duration represented by s, or an error
+	// if s is not in a format this parser understands.
+	ParseTTL(s string) (time.Duration, error)
+}
+
+// TTLParserFunc is a function that implements TTLParser.
+type TTLParserFunc func(s string) (time.Duration, error)
+
+// ParseTTL calls f(s).
+func (f TTLParserFunc) ParseTTL(s string) (time.Duration, error) { return f(s) }
+
+// TTLParsers is a list of parsers for the TTL formats used by one
+// provider's API, in addition to the default formats understood by
+// ParseTTL. Each provider package keeps its own, so that the formats it
+// adds do not change how TTLs from other providers are parsed:
+//
+//	var ttlParsers = libdns.TTLParsers{iso8601Parser}
+//
+//	ttl, err := ttlParsers.ParseTTL(apiRecord.TTL)
+type TTLParsers []TTLParser
+
+// ParseTTL parses a TTL string returned by a provider. If s is not in
+// one of the default formats of the package-level ParseTTL, the parsers
+// in ps are tried in order. Negative TTLs are an error.
+func (ps TTLParsers) ParseTTL(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+
+	ttl, err := parseDefaultTTL(s)
+	if err != nil {
+		for _, p := range ps {
+			if ttl, err = p.ParseTTL(s); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return 0, fmt.Errorf("unrecognized TTL format: %q", s)
+		}
+	}
+
+	if ttl < 0 {
+		return 0, fmt.Errorf("TTL cannot be negative: %s", s)
+	}
+	return ttl, nil
+}
+
+// ParseTTL parses a TTL string returned by a provider. It understands
+// a plain number of seconds (e.g. "300") and Go duration strings (e.g.
+// "5m"); providers with other formats can add parsers for them with
+// TTLParsers. Negative TTLs are an error.
+func ParseTTL(s string) (time.Duration, error) {
+	return TTLParsers(nil).ParseTTL(s)
+}
+
+func parseDefaultTTL(s string) (time.Duration, error) {
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(secs) * time.Second, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// TTLSentinels maps TTL values, in seconds, that a provider's API uses
+// as sentinels with a special meaning rather than as literal TTLs, to
+// the durations they mean. For example, Cloudflare uses a TTL of 1 for
+// "automatic", which is 300 seconds:
+//
+//	var cloudflareSentinels = libdns.TTLSentinels{1: 5 * time.Minute}
+//
+// Each provider package defines its own sentinels, so that a genuine
+// TTL with the same value at another provider is not misinterpreted.
+type TTLSentinels map[int]time.Duration
+
+// Resolve returns the duration meant by a TTL of sec seconds and true
+// if sec is one of the sentinels in s. Otherwise, it returns sec as a
+// duration and false.
+func (s TTLSentinels) Resolve(sec int) (time.Duration, bool) {
+	meaning, ok := s[sec]
+	if !ok {
+		return time.Duration(sec) * time.Second, false
+	}
+	return meaning, true
+}
+
+var (
+	ttlSentinels   = make(map[string]TTLSentinels)
+	ttlSentinelsMu sync.RWMutex
+)
+
+// RegisterTTLSentinel records that a TTL of providerValue seconds, as
+// returned by the API of the named provider, is a sentinel that means
+// the given duration. Sentinels are kept separately for each provider
+// name, so they do not affect TTLs from other providers. Provider
+// packages should call this from an init function, using the name of
+// their package (e.g. "cloudflare"), or keep their own TTLSentinels.
+func RegisterTTLSentinel(provider string, providerValue int, meaning time.Duration) {
+	ttlSentinelsMu.Lock()
+	defer ttlSentinelsMu.Unlock()
+	if ttlSentinels[provider] == nil {
+		ttlSentinels[provider] = make(TTLSentinels)
+	}
+	ttlSentinels[provider][providerValue] = meaning
+}
+
+// ResolveTTLSentinel is like TTLSentinels.Resolve, using the sentinels
+// registered for the named provider with RegisterTTLSentinel.
+func ResolveTTLSentinel(provider string, sec int) (time.Duration, bool) {
+	ttlSentinelsMu.RLock()
+	defer ttlSentinelsMu.RUnlock()
+	return ttlSentinels[provider].Resolve(sec)
+}
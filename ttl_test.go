@@ -0,0 +1,109 @@
+package libdns
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTTL(t *testing.T) {
+	// a simplistic ISO-8601 parser that only understands "PT<n>S"
+	parsers := TTLParsers{TTLParserFunc(func(s string) (time.Duration, error) {
+		if !strings.HasPrefix(s, "PT") || !strings.HasSuffix(s, "S") {
+			return 0, fmt.Errorf("not an ISO-8601 duration in seconds")
+		}
+		return time.ParseDuration(strings.ToLower(s[2:]))
+	})}
+
+	for i, test := range []struct {
+		input     string
+		expect    time.Duration
+		shouldErr bool
+	}{
+		{input: "300", expect: 300 * time.Second},
+		{input: " 60 ", expect: time.Minute},
+		{input: "0", expect: 0},
+		{input: "1h30m", expect: 90 * time.Minute},
+		{input: "PT3600S", expect: time.Hour},
+		{input: "-5", shouldErr: true},
+		{input: "", shouldErr: true},
+		{input: "five minutes", shouldErr: true},
+	} {
+		actual, err := parsers.ParseTTL(test.input)
+		if test.shouldErr {
+			if err == nil {
+				t.Errorf("Test %d: input=%q - expected error but got none", i, test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: input=%q - expected no error but got: %v", i, test.input, err)
+			continue
+		}
+		if actual != test.expect {
+			t.Errorf("Test %d: input=%q - expected %s but got %s", i, test.input, test.expect, actual)
+		}
+	}
+}
+
+func TestParseTTLDefaults(t *testing.T) {
+	if ttl, err := ParseTTL("300"); err != nil || ttl != 5*time.Minute {
+		t.Errorf("Expected 5m0s but got %s (err=%v)", ttl, err)
+	}
+	// formats added by one provider's parsers are not understood by default
+	if _, err := ParseTTL("PT3600S"); err == nil {
+		t.Errorf("Expected error for format without parser but got none")
+	}
+}
+
+func TestTTLSentinelsResolve(t *testing.T) {
+	sentinels := TTLSentinels{1: 5 * time.Minute}
+
+	for i, test := range []struct {
+		sentinels TTLSentinels
+		input     int
+		expect    time.Duration
+		expectOK  bool
+	}{
+		{sentinels: sentinels, input: 1, expect: 5 * time.Minute, expectOK: true},
+		{sentinels: sentinels, input: 3600, expect: time.Hour, expectOK: false},
+		{sentinels: sentinels, input: 0, expect: 0, expectOK: false},
+		// another provider's genuine 1-second TTL is not affected
+		{sentinels: TTLSentinels{}, input: 1, expect: time.Second, expectOK: false},
+		{sentinels: nil, input: 1, expect: time.Second, expectOK: false},
+	} {
+		actual, ok := test.sentinels.Resolve(test.input)
+		if actual != test.expect || ok != test.expectOK {
+			t.Errorf("Test %d: input=%d - expected (%s, %t) but got (%s, %t)",
+				i, test.input, test.expect, test.expectOK, actual, ok)
+		}
+	}
+}
+
+func TestResolveTTLSentinel(t *testing.T) {
+	RegisterTTLSentinel("test-provider", 1, 5*time.Minute)
+	t.Cleanup(func() {
+		ttlSentinelsMu.Lock()
+		delete(ttlSentinels, "test-provider")
+		ttlSentinelsMu.Unlock()
+	})
+
+	for i, test := range []struct {
+		provider string
+		input    int
+		expect   time.Duration
+		expectOK bool
+	}{
+		{provider: "test-provider", input: 1, expect: 5 * time.Minute, expectOK: true},
+		{provider: "test-provider", input: 3600, expect: time.Hour, expectOK: false},
+		// another provider's genuine 1-second TTL is not affected
+		{provider: "other-provider", input: 1, expect: time.Second, expectOK: false},
+	} {
+		actual, ok := ResolveTTLSentinel(test.provider, test.input)
+		if actual != test.expect || ok != test.expectOK {
+			t.Errorf("Test %d: provider=%s input=%d - expected (%s, %t) but got (%s, %t)",
+				i, test.provider, test.input, test.expect, test.expectOK, actual, ok)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package libdns
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// Limits on the size of domain names, per RFC 1035 section 3.1.
+const (
+	maxNameLabels = 127
+	maxNameOctets = 255
+)
+
+// ValidateNameDepth returns an error if name has more than 127 labels
+// or would occupy more than 255 octets in wire format. The name may be
+// relative or fully-qualified; these limits are almost always exceeded
+// because of a bug, such as a zone name being appended twice.
+func ValidateNameDepth(name string) error {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || name == "@" {
+		return nil
+	}
+
+	labels := strings.Count(name, ".") + 1
+	if labels > maxNameLabels {
+		return fmt.Errorf("name has %d labels, exceeding the limit of %d", labels, maxNameLabels)
+	}
+
+	// in wire format, each label is preceded by a length octet and
+	// the name ends with the zero-length root label
+	octets := len(name) + 2
+	if octets > maxNameOctets {
+		return fmt.Errorf("name is %d octets long, exceeding the limit of %d", octets, maxNameOctets)
+	}
+
+	return nil
+}
+
+// ValidateValue performs a cheap syntax check of value as the data of a
+// record of type typ. The value is expected in the form it takes in a
+// zone file, i.e. including fields such as the MX preference or SRV
+// priority and weight which a Record carries in separate fields. Types
+// which are not recognized are not checked.
+func ValidateValue(typ, value string) error {
+	typ = strings.ToUpper(typ)
+	fields := strings.Fields(value)
+
+	switch typ {
+	case "A", "AAAA":
+		ip, err := netip.ParseAddr(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid IP address %q: %v", value, err)
+		}
+		if typ == "A" && !ip.Is4() {
+			return fmt.Errorf("A record value is not an IPv4 address: %s", value)
+		}
+		if typ == "AAAA" && !ip.Is6() {
+			return fmt.Errorf("AAAA record value is not an IPv6 address: %s", value)
+		}
+
+	case "CNAME", "DNAME", "NS", "PTR", "ALIAS":
+		if len(fields) != 1 {
+			return fmt.Errorf("malformed %s value; expected: '<target>'", typ)
+		}
+		return validateDomainName(fields[0])
+
+	case "MX":
+		if len(fields) != 2 {
+			return fmt.Errorf("malformed MX value; expected: '<preference> <exchange>'")
+		}
+		if _, err := strconv.ParseUint(fields[0], 10, 16); err != nil {
+			return fmt.Errorf("invalid preference %s: %v", fields[0], err)
+		}
+		return validateDomainName(fields[1])
+
+	case "SRV":
+		if len(fields) != 4 {
+			return fmt.Errorf("malformed SRV value; expected: '<priority> <weight> <port> <target>'")
+		}
+		for _, field := range fields[:3] {
+			if _, err := strconv.ParseUint(field, 10, 16); err != nil {
+				return fmt.Errorf("invalid number %s: %v", field, err)
+			}
+		}
+		return validateDomainName(fields[3])
+
+	case "CAA":
+		if len(fields) < 3 {
+			return fmt.Errorf("malformed CAA value; expected: '<flags> <tag> <value>'")
+		}
+		if _, err := strconv.ParseUint(fields[0], 10, 8); err != nil {
+			return fmt.Errorf("invalid flags %s: %v", fields[0], err)
+		}
+		if !isAlphanumeric(fields[1]) {
+			return fmt.Errorf("invalid CAA tag: %s", fields[1])
+		}
+	}
+
+	return nil
+}
+
+// validateDomainName checks that name is syntactically usable as a
+// domain name in record data; "." (the root) is allowed.
+func validateDomainName(name string) error {
+	if name == "." {
+		return nil
+	}
+	if name == "" {
+		return fmt.Errorf("empty domain name")
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if label == "" {
+			return fmt.Errorf("domain name has an empty label: %s", name)
+		}
+		if len(label) > 63 {
+			return fmt.Errorf("domain name has a label longer than 63 octets: %s", name)
+		}
+	}
+	return ValidateNameDepth(name)
+}
+
+func isAlphanumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateApex returns an error if recs contain a CNAME record at the
+// zone apex (an empty name or "@"). The apex must have SOA and NS
+// records, which cannot coexist with a CNAME (RFC 1034 section 3.6.2),
+// so an apex CNAME is invalid regardless of the other records in recs.
+// Provider-specific alternatives such as ALIAS records are allowed.
+func ValidateApex(recs []Record) error {
+	for _, rec := range recs {
+		if (rec.Name == "" || rec.Name == "@") && strings.EqualFold(rec.Type, "CNAME") {
+			return fmt.Errorf("CNAME record not allowed at zone apex (target: %s)", rec.Value)
+		}
+	}
+	return nil
+}
+
+// ValidateCAAConsistency returns an error if the CAA records in recs
+// contradict each other at any name:
+//
+//   - an "issue" record forbidding issuance (with no issuer domain, such
+//     as `0 issue ";"`) together with an "issue" record permitting an
+//     issuer; likewise for "issuewild"
+//   - more than one distinct "iodef" target
+//
+// The error reports each conflict. Records of other types are ignored.
+func ValidateCAAConsistency(recs []Record) error {
+	type caaProperty struct {
+		name, tag string
+	}
+	var keys []caaProperty
+	values := make(map[caaProperty][]string)
+	for _, rec := range recs {
+		if !strings.EqualFold(rec.Type, "CAA") {
+			continue
+		}
+		_, tag, value, ok := splitCAA(rec.Value)
+		if !ok {
+			continue
+		}
+		key := caaProperty{
+			name: rrsetKey(rec).Name,
+			tag:  strings.ToLower(tag),
+		}
+		if key.tag != "issue" && key.tag != "issuewild" && key.tag != "iodef" {
+			continue
+		}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		value, _ = unquoteCAAValue(value)
+		values[key] = append(values[key], value)
+	}
+
+	var conflicts []string
+	for _, key := range keys {
+		switch key.tag {
+		case "issue", "issuewild":
+			var forbid, permit bool
+			for _, value := range values[key] {
+				issuer := strings.TrimSpace(strings.SplitN(value, ";", 2)[0])
+				if issuer == "" {
+					forbid = true
+				} else {
+					permit = true
+				}
+			}
+			if forbid && permit {
+				conflicts = append(conflicts, fmt.Sprintf("%s %s both forbids and permits issuance", key.name, key.tag))
+			}
+		case "iodef":
+			distinct := make(map[string]struct{})
+			for _, value := range values[key] {
+				distinct[value] = struct{}{}
+			}
+			if len(distinct) > 1 {
+				conflicts = append(conflicts, fmt.Sprintf("%s has conflicting iodef targets %v", key.name, values[key]))
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("inconsistent CAA records: %s", strings.Join(conflicts, "; "))
+	}
+	return nil
+}
+
+// ValidateRecord checks r for common problems before it is sent to a
+// provider, returning a descriptive error for the first one found:
+//
+//   - a missing type, or a name that is too long or deep
+//   - a missing name for SRV records, whose name must include the
+//     service and protocol
+//   - a priority or weight that does not fit in 16 bits
+//   - data that fails ValidateValue, such as a malformed IP address in
+//     an A or AAAA record or an invalid CAA tag
+//   - a target of an MX, NS or SRV record that is not fully-qualified
+//     (i.e. does not end with a dot)
+func ValidateRecord(r Record) error {
+	if r.Type == "" {
+		return fmt.Errorf("record %q has no type", r.Name)
+	}
+	typ := strings.ToUpper(r.Type)
+
+	if err := ValidateNameDepth(r.Name); err != nil {
+		return fmt.Errorf("invalid name %q: %v", r.Name, err)
+	}
+	if typ == "SRV" && (r.Name == "" || r.Name == "@") {
+		return fmt.Errorf("SRV record requires a name of the form '_service._proto[.name]'")
+	}
+
+	if r.Priority > 65535 {
+		return fmt.Errorf("priority out of range: %d", r.Priority)
+	}
+	if r.Weight > 65535 {
+		return fmt.Errorf("weight out of range: %d", r.Weight)
+	}
+
+	if err := ValidateValue(typ, recordData(r)); err != nil {
+		return err
+	}
+
+	var target string
+	switch typ {
+	case "MX", "NS":
+		target = strings.TrimSpace(r.Value)
+	case "SRV":
+		if fields := strings.Fields(r.Value); len(fields) == 2 {
+			target = fields[1]
+		}
+	}
+	if target != "" && !strings.HasSuffix(target, ".") {
+		return fmt.Errorf("%s target must be fully-qualified (with a trailing dot): %s", typ, target)
+	}
+
+	return nil
+}
+
+// ContainsControlChars reports whether the name or data of r contains
+// raw ASCII control characters, such as a newline or NUL byte. These
+// can break provider APIs or produce malformed zone files, and must be
+// escaped instead (for example, as "\010" in TXT data; see EscapeTXT).
+func ContainsControlChars(r Record) bool {
+	for _, s := range []string{r.Name, r.Value} {
+		for i := 0; i < len(s); i++ {
+			if s[i] < 0x20 || s[i] == 0x7f {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SelfConsistent returns an error if the data of r, as it would be
+// written in a zone file, does not parse back into the same record. This
+// catches records built with data in the wrong fields, such as an MX
+// record with the preference in its Value rather than its Priority.
+func SelfConsistent(r Record) error {
+	if r.Type == "" {
+		return fmt.Errorf("record %q has no type", r.Name)
+	}
+	typ := strings.ToUpper(r.Type)
+
+	data := recordData(r)
+	entries, err := tokenizeZone(data)
+	if err != nil {
+		return fmt.Errorf("%s record data %q: %v", typ, data, err)
+	}
+	if len(entries) != 1 {
+		return fmt.Errorf("%s record data %q does not form a single line", typ, data)
+	}
+	parsed, err := parseRecordData(typ, entries[0].tokens, "")
+	if err != nil {
+		return fmt.Errorf("%s record data %q: %v", typ, data, err)
+	}
+
+	if parsed.Priority != r.Priority || parsed.Weight != r.Weight || parsed.Value != r.Value {
+		return fmt.Errorf("%s record data %q parses to priority %d, weight %d, value %q; expected priority %d, weight %d, value %q",
+			typ, data, parsed.Priority, parsed.Weight, parsed.Value, r.Priority, r.Weight, r.Value)
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package libdns
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateNameDepth(t *testing.T) {
+	for i, test := range []struct {
+		name      string
+		shouldErr bool
+	}{
+		{name: ""},
+		{name: "@"},
+		{name: "www"},
+		{name: "sub.example.com."},
+		{name: strings.Repeat("a.", 126) + "a"},
+		{name: strings.Repeat("a.", 127) + "a", shouldErr: true},
+		{name: strings.Repeat("a", 63) + "." + strings.Repeat("b", 63) + "." + strings.Repeat("c", 63) + "." + strings.Repeat("d", 61)},
+		{name: strings.Repeat("a", 63) + "." + strings.Repeat("b", 63) + "." + strings.Repeat("c", 63) + "." + strings.Repeat("d", 62), shouldErr: true},
+	} {
+		err := ValidateNameDepth(test.name)
+		if test.shouldErr && err == nil {
+			t.Errorf("Test %d: expected error for name of length %d but got none", i, len(test.name))
+		}
+		if !test.shouldErr && err != nil {
+			t.Errorf("Test %d: expected no error but got: %v", i, err)
+		}
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	for i, test := range []struct {
+		typ, value string
+		shouldErr  bool
+	}{
+		{typ: "A", value: "1.2.3.4"},
+		{typ: "A", value: " 1.2.3.4 "},
+		{typ: "A", value: "1.2.3", shouldErr: true},
+		{typ: "A", value: "example.com", shouldErr: true},
+		{typ: "A", value: "2001:db8::1", shouldErr: true},
+		{typ: "AAAA", value: "2001:db8::1"},
+		{typ: "aaaa", value: "1.2.3.4", shouldErr: true},
+		{typ: "AAAA", value: "1.2.3.4", shouldErr: true},
+		{typ: "CNAME", value: "example.com."},
+		{typ: "CNAME", value: "example..com", shouldErr: true},
+		{typ: "CNAME", value: "a b", shouldErr: true},
+		{typ: "NS", value: "ns1.example.com."},
+		{typ: "MX", value: "10 mail.example.com."},
+		{typ: "MX", value: "0 ."},
+		{typ: "MX", value: "mail.example.com.", shouldErr: true},
+		{typ: "MX", value: "70000 mail.example.com.", shouldErr: true},
+		{typ: "SRV", value: "10 20 5223 xmpp.example.com."},
+		{typ: "SRV", value: "5223 xmpp.example.com.", shouldErr: true},
+		{typ: "CAA", value: `0 issue "letsencrypt.org"`},
+		{typ: "CAA", value: `0 is-sue "letsencrypt.org"`, shouldErr: true},
+		{typ: "CAA", value: `issue "letsencrypt.org"`, shouldErr: true},
+		{typ: "TXT", value: "anything goes"},
+		{typ: "UNKNOWN", value: "\\# 0"},
+	} {
+		err := ValidateValue(test.typ, test.value)
+		if test.shouldErr && err == nil {
+			t.Errorf("Test %d: %s %q - expected error but got none", i, test.typ, test.value)
+		}
+		if !test.shouldErr && err != nil {
+			t.Errorf("Test %d: %s %q - expected no error but got: %v", i, test.typ, test.value, err)
+		}
+	}
+}
+
+func TestValidateApex(t *testing.T) {
+	for i, test := range []struct {
+		recs      []Record
+		shouldErr bool
+	}{
+		{
+			recs:      []Record{{Type: "CNAME", Name: "@", Value: "example.net."}},
+			shouldErr: true,
+		},
+		{
+			recs: []Record{
+				{Type: "A", Name: "www", Value: "1.2.3.4"},
+				{Type: "cname", Name: "", Value: "example.net."},
+			},
+			shouldErr: true,
+		},
+		{
+			recs: []Record{{Type: "CNAME", Name: "www", Value: "example.net."}},
+		},
+		{
+			recs: []Record{{Type: "ALIAS", Name: "@", Value: "example.net."}},
+		},
+	} {
+		err := ValidateApex(test.recs)
+		if test.shouldErr && err == nil {
+			t.Errorf("Test %d: expected error but got none", i)
+		}
+		if !test.shouldErr && err != nil {
+			t.Errorf("Test %d: expected no error but got: %v", i, err)
+		}
+	}
+}
+
+func TestValidateRecord(t *testing.T) {
+	for i, test := range []struct {
+		rec       Record
+		shouldErr bool
+	}{
+		// happy paths
+		{rec: Record{Type: "A", Name: "www", Value: "192.0.2.1"}},
+		{rec: Record{Type: "AAAA", Name: "@", Value: "2001:db8::1"}},
+		{rec: Record{Type: "A", Name: "@", Value: " 1.2.3.4"}},
+		{rec: Record{Type: "CNAME", Name: "www", Value: "example.com."}},
+		{rec: Record{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10}},
+		{rec: Record{Type: "MX", Name: "@", Value: ".", Priority: 0}},
+		{rec: Record{Type: "NS", Name: "sub", Value: "ns1.example.com."}},
+		{rec: Record{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 10, Weight: 5}},
+		{rec: Record{Type: "TXT", Name: "@", Value: "v=spf1 -all"}},
+		{rec: Record{Type: "CAA", Name: "@", Value: `0 issue "letsencrypt.org"`}},
+
+		// missing type or name
+		{rec: Record{Name: "www", Value: "192.0.2.1"}, shouldErr: true},
+		{rec: Record{Type: "SRV", Name: "", Value: "5060 sip.example.com."}, shouldErr: true},
+		{rec: Record{Type: "A", Name: strings.Repeat("a.", 128), Value: "192.0.2.1"}, shouldErr: true},
+
+		// malformed IPs
+		{rec: Record{Type: "A", Name: "www", Value: "192.0.2"}, shouldErr: true},
+		{rec: Record{Type: "AAAA", Name: "www", Value: "192.0.2.1"}, shouldErr: true},
+
+		// non-FQDN targets
+		{rec: Record{Type: "MX", Name: "@", Value: "mail.example.com", Priority: 10}, shouldErr: true},
+		{rec: Record{Type: "NS", Name: "sub", Value: "ns1"}, shouldErr: true},
+		{rec: Record{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip"}, shouldErr: true},
+
+		// out-of-range numbers
+		{rec: Record{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 65536}, shouldErr: true},
+		{rec: Record{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Weight: 70000}, shouldErr: true},
+		{rec: Record{Type: "SRV", Name: "_sip._tcp", Value: "65536 sip.example.com."}, shouldErr: true},
+
+		// invalid CAA tag
+		{rec: Record{Type: "CAA", Name: "@", Value: `0 is_sue "letsencrypt.org"`}, shouldErr: true},
+	} {
+		err := ValidateRecord(test.rec)
+		if test.shouldErr && err == nil {
+			t.Errorf("Test %d: expected error for %+v but got none", i, test.rec)
+		}
+		if !test.shouldErr && err != nil {
+			t.Errorf("Test %d: expected no error for %+v but got: %v", i, test.rec, err)
+		}
+	}
+}
+
+func TestContainsControlChars(t *testing.T) {
+	for i, test := range []struct {
+		rec    Record
+		expect bool
+	}{
+		{rec: Record{Type: "TXT", Name: "@", Value: "v=spf1 -all"}, expect: false},
+		{rec: Record{Type: "TXT", Name: "@", Value: `line one\010line two`}, expect: false},
+		{rec: Record{Type: "TXT", Name: "@", Value: "naïve"}, expect: false},
+		{rec: Record{Type: "TXT", Name: "@", Value: "line one\nline two"}, expect: true},
+		{rec: Record{Type: "TXT", Name: "@", Value: "tab\there"}, expect: true},
+		{rec: Record{Type: "TXT", Name: "@", Value: "nul\x00"}, expect: true},
+		{rec: Record{Type: "TXT", Name: "@", Value: "del\x7f"}, expect: true},
+		{rec: Record{Type: "A", Name: "www\r", Value: "192.0.2.1"}, expect: true},
+	} {
+		actual := ContainsControlChars(test.rec)
+		if actual != test.expect {
+			t.Errorf("Test %d: for %+v expected %t but got %t", i, test.rec, test.expect, actual)
+		}
+	}
+}
+
+func TestValidateCAAConsistency(t *testing.T) {
+	for i, test := range []struct {
+		recs      []Record
+		shouldErr bool
+	}{
+		{
+			recs: []Record{
+				{Type: "CAA", Name: "@", Value: `0 issue "letsencrypt.org"`},
+				{Type: "CAA", Name: "@", Value: `0 issue "pki.goog; cansignhttpexchanges=yes"`},
+				{Type: "CAA", Name: "@", Value: `0 issuewild ";"`},
+				{Type: "CAA", Name: "@", Value: `0 iodef "mailto:security@example.com"`},
+				{Type: "CAA", Name: "sub", Value: `0 issue ";"`},
+				{Type: "TXT", Name: "@", Value: `0 issue ";"`},
+			},
+			shouldErr: false,
+		},
+		{
+			recs: []Record{
+				{Type: "CAA", Name: "@", Value: `0 issue ";"`},
+				{Type: "CAA", Name: "", Value: `0 issue "letsencrypt.org"`},
+			},
+			shouldErr: true,
+		},
+		{
+			recs: []Record{
+				{Type: "CAA", Name: "@", Value: `0  issue ";"`},
+				{Type: "CAA", Name: "@", Value: "0\tissue  \"letsencrypt.org\""},
+			},
+			shouldErr: true,
+		},
+		{
+			recs: []Record{
+				{Type: "CAA", Name: "www", Value: `0 issuewild "letsencrypt.org"`},
+				{Type: "caa", Name: "WWW", Value: `0 issuewild ""`},
+			},
+			shouldErr: true,
+		},
+		{
+			recs: []Record{
+				{Type: "CAA", Name: "@", Value: `0 iodef "mailto:security@example.com"`},
+				{Type: "CAA", Name: "@", Value: `0 iodef "https://example.com/caa"`},
+			},
+			shouldErr: true,
+		},
+		{
+			recs: []Record{
+				{Type: "CAA", Name: "@", Value: `0 iodef "mailto:security@example.com"`},
+				{Type: "CAA", Name: "@", Value: `0 iodef mailto:security@example.com`},
+			},
+			shouldErr: false,
+		},
+		{
+			recs: []Record{
+				{Type: "CAA", Name: "@", Value: `0 iodef "mailto:security@example.com"`},
+				{Type: "CAA", Name: "@", Value: `0 iodef "mailto:security\064example.com"`},
+			},
+			shouldErr: false,
+		},
+	} {
+		err := ValidateCAAConsistency(test.recs)
+		if test.shouldErr && err == nil {
+			t.Errorf("Test %d: expected error but got none", i)
+		}
+		if !test.shouldErr && err != nil {
+			t.Errorf("Test %d: expected no error but got: %v", i, err)
+		}
+	}
+}
+
+func TestSelfConsistent(t *testing.T) {
+	for i, test := range []struct {
+		rec       Record
+		shouldErr bool
+	}{
+		{rec: Record{Type: "A", Name: "www", Value: "192.0.2.1"}},
+		{rec: Record{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10}},
+		{rec: Record{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 10, Weight: 5}},
+		{rec: Record{Type: "TXT", Name: "@", Value: `v=spf1 include:"x" -all`}},
+		{rec: Record{Type: "TXT", Name: "@", Value: strings.Repeat("a", 300)}},
+		{rec: Record{Type: "CAA", Name: "@", Value: `0 issue "letsencrypt.org"`}},
+		{rec: Record{Type: "HTTPS", Name: "@", Value: `. alpn="h2,h3"`, Priority: 1}},
+		{
+			// priority and weight baked into the value
+			rec:       Record{Type: "SRV", Name: "_sip._tcp", Value: "10 5 5060 sip.example.com."},
+			shouldErr: true,
+		},
+		{
+			// preference baked into the target
+			rec:       Record{Type: "MX", Name: "@", Value: "10 mail.example.com."},
+			shouldErr: true,
+		},
+		{
+			rec:       Record{Type: "CNAME", Name: "www", Value: "example.com.\nexample.net."},
+			shouldErr: true,
+		},
+		{
+			rec:       Record{Name: "www", Value: "192.0.2.1"},
+			shouldErr: true,
+		},
+	} {
+		err := SelfConsistent(test.rec)
+		if test.shouldErr && err == nil {
+			t.Errorf("Test %d: expected error for %+v but got none", i, test.rec)
+		}
+		if !test.shouldErr && err != nil {
+			t.Errorf("Test %d: expected no error for %+v but got: %v", i, test.rec, err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package libdns
+
+import (
+	"context"
+	"fmt"
+)
+
+// VerifyExist checks that all the records in want exist in zone. It gets
+// the zone's records with a single call to g.GetRecords and compares them
+// to want with RecordsEqual, returning the records in want that were not
+// found. If want is empty, no records are retrieved.
+func VerifyExist(ctx context.Context, g RecordGetter, zone string, want []Record) (missing []Record, err error) {
+	if len(want) == 0 {
+		return nil, nil
+	}
+	got, err := g.GetRecords(ctx, zone)
+	if err != nil {
+		return nil, fmt.Errorf("getting records: %w", err)
+	}
+	for _, w := range want {
+		found := false
+		for _, rec := range got {
+			if RecordsEqual(w, rec, zone) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, w)
+		}
+	}
+	return missing, nil
+}
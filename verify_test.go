@@ -0,0 +1,75 @@
+package libdns
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type staticGetter struct {
+	recs  []Record
+	calls int
+}
+
+func (g *staticGetter) GetRecords(ctx context.Context, zone string) ([]Record, error) {
+	g.calls++
+	return g.recs, nil
+}
+
+func TestVerifyExist(t *testing.T) {
+	existing := []Record{
+		{ID: "1", Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+		{ID: "2", Type: "A", Name: "www", Value: "192.0.2.2", TTL: time.Hour},
+		{ID: "3", Type: "CNAME", Name: "blog", Value: "www.example.com.", TTL: time.Hour},
+	}
+
+	for i, test := range []struct {
+		want      []Record
+		expect    []Record
+		wantCalls int
+	}{
+		{
+			// all present; names and values in other forms
+			want: []Record{
+				{Type: "A", Name: "www.example.com.", Value: "192.0.2.1", TTL: time.Hour},
+				{Type: "a", Name: "www", Value: "192.0.2.2", TTL: time.Hour},
+				{Type: "CNAME", Name: "blog", Value: "WWW.example.com", TTL: time.Hour},
+			},
+			expect:    nil,
+			wantCalls: 1,
+		},
+		{
+			// some missing
+			want: []Record{
+				{Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+				{Type: "A", Name: "www", Value: "192.0.2.3", TTL: time.Hour},
+				{Type: "A", Name: "www", Value: "192.0.2.2", TTL: time.Minute},
+			},
+			expect: []Record{
+				{Type: "A", Name: "www", Value: "192.0.2.3", TTL: time.Hour},
+				{Type: "A", Name: "www", Value: "192.0.2.2", TTL: time.Minute},
+			},
+			wantCalls: 1,
+		},
+		{
+			// empty want
+			want:      nil,
+			expect:    nil,
+			wantCalls: 0,
+		},
+	} {
+		getter := &staticGetter{recs: existing}
+		actual, err := VerifyExist(context.Background(), getter, "example.com.", test.want)
+		if err != nil {
+			t.Errorf("Test %d: expected no error but got: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, test.expect) {
+			t.Errorf("Test %d: expected missing %+v but got %+v", i, test.expect, actual)
+		}
+		if getter.calls != test.wantCalls {
+			t.Errorf("Test %d: expected %d calls to GetRecords but got %d", i, test.wantCalls, getter.calls)
+		}
+	}
+}
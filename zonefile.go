@@ -0,0 +1,637 @@
+package libdns
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MarshalZone serializes recs into the master file format described
+// in RFC 1035 section 5. See WriteZone for details of the output.
+func MarshalZone(zone string, recs []Record) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteZone(&buf, zone, recs); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteZone writes recs to w in the master file format described in
+// RFC 1035 section 5. The output begins with an $ORIGIN directive for
+// zone and, if there are any records, a $TTL directive with the most
+// common TTL among them, followed by one line per record of the form:
+//
+//	<name> <ttl> IN <type> <data>
+//
+// Names may be relative to zone or fully-qualified; they are written
+// fully-qualified, and every record has an explicit TTL. Records are
+// grouped by RRset in the order each RRset first appears in recs,
+// except that the SOA record, if any, is written first by convention.
+// TXT values are split into quoted character-strings of at most 255
+// bytes each.
+func WriteZone(w io.Writer, zone string, recs []Record) error {
+	if zone == "" {
+		return fmt.Errorf("zone name is required")
+	}
+	origin := strings.TrimSuffix(zone, ".") + "."
+
+	// group records by RRset, keeping the RRsets in input order
+	var keys []RRSetKey
+	groups := make(map[RRSetKey][]Record)
+	for _, rec := range recs {
+		if rec.Type == "" {
+			return fmt.Errorf("record %q has no type", rec.Name)
+		}
+		key := rrsetKeyInZone(rec, origin)
+		if _, ok := groups[key]; !ok {
+			if key.Type == "SOA" {
+				keys = append([]RRSetKey{key}, keys...)
+			} else {
+				keys = append(keys, key)
+			}
+		}
+		groups[key] = append(groups[key], rec)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "$ORIGIN %s\n", origin)
+	if len(recs) > 0 {
+		fmt.Fprintf(&buf, "$TTL %d\n", int64(commonTTL(recs).Seconds()))
+	}
+	for _, key := range keys {
+		for _, rec := range groups[key] {
+			fmt.Fprintf(&buf, "%s\t%d\tIN\t%s\t%s\n",
+				qualifiedName(rec.Name, origin),
+				int64(rec.TTL.Seconds()),
+				strings.ToUpper(rec.Type),
+				recordData(rec))
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// commonTTL returns the most common TTL among recs, preferring the
+// TTL seen first in case of a tie.
+func commonTTL(recs []Record) time.Duration {
+	counts := make(map[time.Duration]int)
+	for _, rec := range recs {
+		counts[rec.TTL]++
+	}
+	var common time.Duration
+	for _, rec := range recs {
+		if counts[rec.TTL] > counts[common] {
+			common = rec.TTL
+		}
+	}
+	return common
+}
+
+// recordData returns the data of rec as it appears in a zone file,
+// i.e. with the type-dependent fields that a Record carries separately
+// from its Value put back in their place.
+func recordData(rec Record) string {
+	switch strings.ToUpper(rec.Type) {
+	case "MX", "HTTPS", "SVCB":
+		return fmt.Sprintf("%d %s", rec.Priority, rec.Value)
+	case "SRV", "URI":
+		return fmt.Sprintf("%d %d %s", rec.Priority, rec.Weight, rec.Value)
+	case "TXT", "SPF":
+		chunks := SplitTXT(rec.Value)
+		for i, chunk := range chunks {
+			chunks[i] = quoteCharacterString(chunk)
+		}
+		return strings.Join(chunks, " ")
+	}
+	return rec.Value
+}
+
+// maxCharacterStringLen is the maximum length of a single
+// character-string (RFC 1035 section 3.3).
+const maxCharacterStringLen = 255
+
+// SplitTXT splits text, the value of a TXT record, into chunks that
+// each fit in a single character-string of at most 255 bytes, as is
+// required for longer values such as DKIM keys. Chunks end on UTF-8
+// character boundaries, so a multibyte character is never split
+// between two chunks. An empty text yields a single empty chunk.
+func SplitTXT(text string) []string {
+	var chunks []string
+	for len(text) > maxCharacterStringLen {
+		end := maxCharacterStringLen
+		for end > 0 && !utf8.RuneStart(text[end]) {
+			end--
+		}
+		if end == 0 {
+			// not valid UTF-8; split anywhere
+			end = maxCharacterStringLen
+		}
+		chunks = append(chunks, text[:end])
+		text = text[end:]
+	}
+	return append(chunks, text)
+}
+
+// JoinTXT returns the value of a TXT record from its data as it appears
+// in a zone file: one or more character-strings, quoted or not, which
+// are unescaped and concatenated. For example, the data
+// "part one" "part two" yields "part onepart two". It is the inverse
+// of quoting and joining the chunks returned by SplitTXT.
+func JoinTXT(data string) (string, error) {
+	entries, err := tokenizeZone(data)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) > 1 {
+		return "", fmt.Errorf("TXT data spans multiple lines")
+	}
+
+	var sb strings.Builder
+	for _, entry := range entries {
+		for _, tok := range entry.tokens {
+			s, err := unquoteCharacterString(tok)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(s)
+		}
+	}
+	return sb.String(), nil
+}
+
+// quoteCharacterString returns s as a quoted character-string.
+func quoteCharacterString(s string) string {
+	return `"` + EscapeTXT(s) + `"`
+}
+
+// EscapeTXT escapes s for use within a quoted character-string in a
+// zone file, such as the data of a TXT record: quotes and backslashes
+// are escaped with a backslash, and bytes that are not printable ASCII
+// (including the bytes of multibyte UTF-8 characters) are written as
+// decimal \DDD escapes. UnescapeTXT reverses the escaping.
+func EscapeTXT(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c < ' ' || c > '~':
+			fmt.Fprintf(&sb, "\\%03d", c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// UnmarshalZone parses a zone in the master file format described in
+// RFC 1035 section 5, returning the name of the zone and its records.
+// The $ORIGIN and $TTL directives are supported, as are relative and
+// absolute owner names, "@", omitted owner names and TTLs, comments,
+// and records spanning multiple lines with parentheses.
+//
+// The zone name is the origin given by the first $ORIGIN directive, or
+// the owner of the SOA record if there is no $ORIGIN. Record names are
+// returned relative to the zone, with "@" for the apex. Domain names
+// in the data of CNAME, DNAME, NS, PTR, MX, SRV and SOA records are
+// fully-qualified, as relative names in a zone file are relative to
+// the origin. Records without a TTL, and without a preceding $TTL or
+// explicit TTL to inherit, have a TTL of 0.
+func UnmarshalZone(data []byte) (string, []Record, error) {
+	return parseZone(data, "")
+}
+
+// ParseZoneFile reads a zone in the master file format from r, like
+// UnmarshalZone, and returns its records with names relative to zone.
+// The zone is also the initial origin, so the input does not need an
+// $ORIGIN directive; records outside of zone are an error.
+func ParseZoneFile(r io.Reader, zone string) ([]Record, error) {
+	if zone == "" {
+		return nil, fmt.Errorf("zone name is required")
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	_, recs, err := parseZone(data, strings.TrimSuffix(zone, ".")+".")
+	return recs, err
+}
+
+// parseZone parses data as a zone file. If zone is not empty, it is the
+// fully-qualified name of the zone and the initial origin; otherwise
+// the zone is determined from the data as described for UnmarshalZone.
+func parseZone(data []byte, zone string) (string, []Record, error) {
+	entries, err := tokenizeZone(string(data))
+	if err != nil {
+		return "", nil, err
+	}
+
+	var (
+		origin         = zone
+		owner          string
+		defaultTTL     time.Duration
+		haveDefaultTTL bool
+		lastTTL        time.Duration
+		recs           []Record
+		owners         []string // FQDN of each record in recs
+	)
+
+	for _, entry := range entries {
+		tokens := entry.tokens
+
+		// directives
+		switch strings.ToUpper(tokens[0]) {
+		case "$ORIGIN":
+			if len(tokens) != 2 {
+				return "", nil, fmt.Errorf("line %d: malformed $ORIGIN directive", entry.line)
+			}
+			origin = qualifyName(tokens[1], origin)
+			if !strings.HasSuffix(origin, ".") {
+				return "", nil, fmt.Errorf("line %d: $ORIGIN must be fully-qualified: %s", entry.line, origin)
+			}
+			if zone == "" {
+				zone = origin
+			}
+			continue
+		case "$TTL":
+			if len(tokens) != 2 {
+				return "", nil, fmt.Errorf("line %d: malformed $TTL directive", entry.line)
+			}
+			defaultTTL, err = parseZoneTTL(tokens[1])
+			if err != nil {
+				return "", nil, fmt.Errorf("line %d: %v", entry.line, err)
+			}
+			haveDefaultTTL = true
+			continue
+		}
+		if strings.HasPrefix(tokens[0], "$") {
+			return "", nil, fmt.Errorf("line %d: unsupported directive: %s", entry.line, tokens[0])
+		}
+
+		// owner name
+		if !entry.blankOwner {
+			owner = qualifyName(tokens[0], origin)
+			tokens = tokens[1:]
+		}
+		if owner == "" {
+			return "", nil, fmt.Errorf("line %d: record has no owner name", entry.line)
+		}
+		if !strings.HasSuffix(owner, ".") {
+			return "", nil, fmt.Errorf("line %d: owner name %s is relative, but no $ORIGIN is set", entry.line, owner)
+		}
+
+		// TTL and class, in either order, both optional
+		ttl, haveTTL := time.Duration(0), false
+		for i := 0; i < 2 && len(tokens) > 0; i++ {
+			if t, err := parseZoneTTL(tokens[0]); err == nil && !haveTTL {
+				ttl, haveTTL = t, true
+				tokens = tokens[1:]
+			} else if strings.EqualFold(tokens[0], "IN") {
+				tokens = tokens[1:]
+			}
+		}
+		switch {
+		case haveTTL:
+			lastTTL = ttl
+		case haveDefaultTTL:
+			ttl = defaultTTL
+		default:
+			ttl = lastTTL
+		}
+
+		if len(tokens) == 0 {
+			return "", nil, fmt.Errorf("line %d: record has no type", entry.line)
+		}
+		rec, err := parseRecordData(strings.ToUpper(tokens[0]), tokens[1:], origin)
+		if err != nil {
+			return "", nil, fmt.Errorf("line %d: %v", entry.line, err)
+		}
+		rec.TTL = ttl
+
+		if zone == "" && rec.Type == "SOA" {
+			zone = owner
+		}
+		recs = append(recs, rec)
+		owners = append(owners, owner)
+	}
+
+	if zone == "" {
+		return "", nil, fmt.Errorf("cannot determine zone name: no $ORIGIN directive or SOA record")
+	}
+	for i, owner := range owners {
+		switch {
+		case strings.EqualFold(owner, zone):
+			recs[i].Name = "@"
+		case strings.HasSuffix(strings.ToLower(owner), "."+strings.ToLower(zone)):
+			recs[i].Name = owner[:len(owner)-len(zone)-1]
+		default:
+			return "", nil, fmt.Errorf("record %s %s is outside of zone %s", owner, recs[i].Type, zone)
+		}
+	}
+
+	return zone, recs, nil
+}
+
+// parseRecordData parses the data of a record of type typ from tokens,
+// qualifying relative domain names in the data with origin. It is the
+// inverse of recordData.
+func parseRecordData(typ string, tokens []string, origin string) (Record, error) {
+	rec := Record{Type: typ}
+	fields := append([]string(nil), tokens...)
+
+	// numeric fields which a Record carries separately from the Value
+	var numFields int
+	switch typ {
+	case "MX", "HTTPS", "SVCB":
+		numFields = 1
+	case "SRV", "URI":
+		numFields = 2
+	}
+	if len(fields) < numFields+1 {
+		return Record{}, fmt.Errorf("%s record has too few fields", typ)
+	}
+	if numFields > 0 {
+		priority, err := strconv.ParseUint(fields[0], 10, 16)
+		if err != nil {
+			return Record{}, fmt.Errorf("invalid %s priority %s: %v", typ, fields[0], err)
+		}
+		rec.Priority = uint(priority)
+	}
+	if numFields > 1 {
+		weight, err := strconv.ParseUint(fields[1], 10, 16)
+		if err != nil {
+			return Record{}, fmt.Errorf("invalid %s weight %s: %v", typ, fields[1], err)
+		}
+		rec.Weight = uint(weight)
+	}
+	fields = fields[numFields:]
+
+	switch typ {
+	case "TXT", "SPF":
+		var sb strings.Builder
+		for _, tok := range tokens {
+			s, err := unquoteCharacterString(tok)
+			if err != nil {
+				return Record{}, err
+			}
+			sb.WriteString(s)
+		}
+		rec.Value = sb.String()
+		return rec, nil
+
+	case "CNAME", "DNAME", "NS", "PTR", "MX":
+		if len(fields) != 1 {
+			return Record{}, fmt.Errorf("malformed %s data: expected a single domain name", typ)
+		}
+		fields[0] = qualifyName(fields[0], origin)
+
+	case "SRV":
+		// "<port> <target>"
+		if len(fields) != 2 {
+			return Record{}, fmt.Errorf("malformed SRV data; expected: '<priority> <weight> <port> <target>'")
+		}
+		fields[1] = qualifyName(fields[1], origin)
+
+	case "SOA":
+		if len(fields) != 7 {
+			return Record{}, fmt.Errorf("malformed SOA data; expected: '<mname> <rname> <serial> <refresh> <retry> <expire> <minimum>'")
+		}
+		fields[0] = qualifyName(fields[0], origin)
+		fields[1] = qualifyName(fields[1], origin)
+		// the timers may be written with units, like TTLs
+		for i, name := range []string{"refresh", "retry", "expire", "minimum"} {
+			d, err := parseZoneTTL(fields[i+3])
+			if err != nil {
+				return Record{}, fmt.Errorf("invalid SOA %s %s: %v", name, fields[i+3], err)
+			}
+			fields[i+3] = strconv.FormatInt(int64(d.Seconds()), 10)
+		}
+	}
+
+	rec.Value = strings.Join(fields, " ")
+	return rec, nil
+}
+
+// qualifyName makes name absolute using origin, as names are resolved
+// in a zone file: "@" is the origin itself, and names without a
+// trailing dot are relative to it. If origin is empty, name is
+// returned unchanged.
+func qualifyName(name, origin string) string {
+	if name == "@" {
+		return origin
+	}
+	if strings.HasSuffix(name, ".") || origin == "" {
+		return name
+	}
+	if origin == "." {
+		return name + "."
+	}
+	return name + "." + origin
+}
+
+// parseZoneTTL parses a TTL in a zone file, which is either a number
+// of seconds or a BIND-style duration like "1h30m" or "1w".
+func parseZoneTTL(s string) (time.Duration, error) {
+	if secs, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return time.Duration(secs) * time.Second, nil
+	}
+
+	if s == "" {
+		return 0, fmt.Errorf("empty TTL")
+	}
+
+	var total, n uint64
+	var haveDigits bool
+	for _, c := range strings.ToLower(s) {
+		if c >= '0' && c <= '9' {
+			n = n*10 + uint64(c-'0')
+			haveDigits = true
+			continue
+		}
+		if !haveDigits {
+			return 0, fmt.Errorf("invalid TTL: %s", s)
+		}
+		switch c {
+		case 's':
+			total += n
+		case 'm':
+			total += n * 60
+		case 'h':
+			total += n * 3600
+		case 'd':
+			total += n * 86400
+		case 'w':
+			total += n * 604800
+		default:
+			return 0, fmt.Errorf("invalid TTL: %s", s)
+		}
+		n, haveDigits = 0, false
+	}
+	if haveDigits {
+		return 0, fmt.Errorf("invalid TTL: %s", s)
+	}
+	return time.Duration(total) * time.Second, nil
+}
+
+// zoneEntry is a single logical line of a zone file, which may span
+// multiple physical lines if parentheses are used. Its tokens are the
+// fields of the entry as they appear in the file, including any quotes
+// and escape sequences.
+type zoneEntry struct {
+	line       int // the physical line on which the entry starts
+	blankOwner bool
+	tokens     []string
+}
+
+// tokenizeZone splits a zone file into entries of tokens, removing
+// comments and joining lines continued with parentheses.
+func tokenizeZone(input string) ([]zoneEntry, error) {
+	var (
+		entries  []zoneEntry
+		current  zoneEntry
+		token    strings.Builder
+		inToken  bool
+		inQuotes bool
+		parens   int
+		line     = 1
+	)
+
+	endToken := func() {
+		if inToken {
+			current.tokens = append(current.tokens, token.String())
+			token.Reset()
+			inToken = false
+		}
+	}
+	endEntry := func() {
+		endToken()
+		if len(current.tokens) > 0 {
+			entries = append(entries, current)
+		}
+		current = zoneEntry{line: line + 1}
+	}
+
+	current.line = line
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+
+		if inQuotes {
+			token.WriteByte(c)
+			switch c {
+			case '\\':
+				if i+1 < len(input) {
+					i++
+					token.WriteByte(input[i])
+					if input[i] == '\n' {
+						line++
+					}
+				}
+			case '"':
+				inQuotes = false
+			case '\n':
+				line++
+			}
+			continue
+		}
+
+		switch c {
+		case '\\':
+			token.WriteByte(c)
+			if i+1 < len(input) {
+				i++
+				token.WriteByte(input[i])
+			}
+			inToken = true
+		case '"':
+			token.WriteByte(c)
+			inToken, inQuotes = true, true
+		case ';':
+			for i+1 < len(input) && input[i+1] != '\n' {
+				i++
+			}
+		case '(':
+			endToken()
+			parens++
+		case ')':
+			endToken()
+			if parens == 0 {
+				return nil, fmt.Errorf("line %d: unbalanced parentheses", line)
+			}
+			parens--
+		case ' ', '\t', '\r':
+			if len(current.tokens) == 0 && !inToken && (i == 0 || input[i-1] == '\n') {
+				current.blankOwner = true
+			}
+			endToken()
+		case '\n':
+			if parens > 0 {
+				endToken()
+			} else {
+				endEntry()
+			}
+			line++
+		default:
+			token.WriteByte(c)
+			inToken = true
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("line %d: unterminated quoted string", line)
+	}
+	if parens > 0 {
+		return nil, fmt.Errorf("line %d: unbalanced parentheses", line)
+	}
+	endEntry()
+
+	return entries, nil
+}
+
+// unquoteCharacterString returns the contents of a character-string as
+// it appears in a zone file, i.e. without surrounding quotes (if any)
+// and with escape sequences resolved.
+func unquoteCharacterString(s string) (string, error) {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	return UnescapeTXT(s)
+}
+
+// UnescapeTXT resolves the escape sequences in s, the contents of a
+// character-string as it appears in a zone file (without surrounding
+// quotes): \DDD is the byte with decimal value DDD, and a backslash
+// followed by any other character is that character. It returns an
+// error if s ends with a lone backslash or contains a decimal escape
+// greater than 255.
+func UnescapeTXT(s string) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			sb.WriteByte(s[i])
+			continue
+		}
+		if i+1 >= len(s) {
+			return "", fmt.Errorf("character-string ends with a backslash: %s", s)
+		}
+		if i+3 < len(s) && isDigit(s[i+1]) && isDigit(s[i+2]) && isDigit(s[i+3]) {
+			n, _ := strconv.Atoi(s[i+1 : i+4])
+			if n > 255 {
+				return "", fmt.Errorf("invalid escape sequence in character-string: %s", s[i:i+4])
+			}
+			sb.WriteByte(byte(n))
+			i += 3
+			continue
+		}
+		sb.WriteByte(s[i+1])
+		i++
+	}
+	return sb.String(), nil
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
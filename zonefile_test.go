@@ -0,0 +1,463 @@
+package libdns
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshalZone(t *testing.T) {
+	longText := strings.Repeat("a", 255) + strings.Repeat("b", 10)
+
+	recs := []Record{
+		{Type: "A", Name: "www", Value: "1.2.3.4", TTL: 5 * time.Minute},
+		{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10, TTL: time.Hour},
+		{Type: "A", Name: "www", Value: "1.2.3.5", TTL: 5 * time.Minute},
+		{Type: "SOA", Name: "@", Value: "ns1.example.com. admin.example.com. 2024010101 7200 3600 1209600 3600", TTL: time.Hour},
+		{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 10, Weight: 20, TTL: time.Hour},
+		{Type: "TXT", Name: "@", Value: `say "hi" \o/`, TTL: time.Hour},
+		{Type: "TXT", Name: "long", Value: longText, TTL: time.Hour},
+	}
+
+	expect := `$ORIGIN example.com.
+$TTL 3600
+example.com.	3600	IN	SOA	ns1.example.com. admin.example.com. 2024010101 7200 3600 1209600 3600
+www.example.com.	300	IN	A	1.2.3.4
+www.example.com.	300	IN	A	1.2.3.5
+example.com.	3600	IN	MX	10 mail.example.com.
+_sip._tcp.example.com.	3600	IN	SRV	10 20 5060 sip.example.com.
+example.com.	3600	IN	TXT	"say \"hi\" \\o/"
+long.example.com.	3600	IN	TXT	"` + strings.Repeat("a", 255) + `" "` + strings.Repeat("b", 10) + `"
+`
+
+	for _, zone := range []string{"example.com.", "example.com"} {
+		actual, err := MarshalZone(zone, recs)
+		if err != nil {
+			t.Fatalf("Zone %s: expected no error but got: %v", zone, err)
+		}
+		if string(actual) != expect {
+			t.Errorf("Zone %s: expected:\n%s\nbut got:\n%s", zone, expect, actual)
+		}
+	}
+
+	// names may also be given fully-qualified
+	fqdnRecs := []Record{
+		{Type: "A", Name: "www", Value: "1.2.3.4", TTL: 5 * time.Minute},
+		{Type: "A", Name: "www.example.com.", Value: "1.2.3.5", TTL: 5 * time.Minute},
+		{Type: "TXT", Name: "example.com.", Value: "hello", TTL: time.Hour},
+	}
+	fqdnExpect := `$ORIGIN example.com.
+$TTL 300
+www.example.com.	300	IN	A	1.2.3.4
+www.example.com.	300	IN	A	1.2.3.5
+example.com.	3600	IN	TXT	"hello"
+`
+	if actual, err := MarshalZone("example.com.", fqdnRecs); err != nil {
+		t.Errorf("Expected no error with FQDN names but got: %v", err)
+	} else if string(actual) != fqdnExpect {
+		t.Errorf("With FQDN names: expected:\n%s\nbut got:\n%s", fqdnExpect, actual)
+	}
+
+	if _, err := MarshalZone("", recs); err == nil {
+		t.Errorf("Expected error with empty zone but got none")
+	}
+	if _, err := MarshalZone("example.com.", []Record{{Name: "www", Value: "1.2.3.4"}}); err == nil {
+		t.Errorf("Expected error with record missing type but got none")
+	}
+}
+
+func TestUnmarshalZone(t *testing.T) {
+	input := `; example zone
+$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	ns1 hostmaster (
+		2024010101 ; serial
+		7200       ; refresh
+		3600       ; retry
+		1209600    ; expire
+		300 )      ; minimum
+	IN	NS	ns1
+	IN	NS	ns2.example.net.
+	IN	MX	10 mail
+@	300	IN	TXT	"v=spf1 mx -all"
+www	300	IN	A	192.0.2.1
+	IN	300	AAAA	2001:db8::1
+WWW.Example.COM.	A	192.0.2.2
+_sip._tcp	SRV	10 20 5060 sip
+alias	CNAME	@
+txt	TXT	( "part one "
+		"part two; not a comment"
+		"say \"hi\"\010" )
+caa	CAA	0 issue "letsencrypt.org"
+`
+	expect := []Record{
+		{Type: "SOA", Name: "@", Value: "ns1.example.com. hostmaster.example.com. 2024010101 7200 3600 1209600 300", TTL: time.Hour},
+		{Type: "NS", Name: "@", Value: "ns1.example.com.", TTL: time.Hour},
+		{Type: "NS", Name: "@", Value: "ns2.example.net.", TTL: time.Hour},
+		{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10, TTL: time.Hour},
+		{Type: "TXT", Name: "@", Value: "v=spf1 mx -all", TTL: 5 * time.Minute},
+		{Type: "A", Name: "www", Value: "192.0.2.1", TTL: 5 * time.Minute},
+		{Type: "AAAA", Name: "www", Value: "2001:db8::1", TTL: 5 * time.Minute},
+		{Type: "A", Name: "WWW", Value: "192.0.2.2", TTL: time.Hour},
+		{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 10, Weight: 20, TTL: time.Hour},
+		{Type: "CNAME", Name: "alias", Value: "example.com.", TTL: time.Hour},
+		{Type: "TXT", Name: "txt", Value: "part one part two; not a comment" + `say "hi"` + "\n", TTL: time.Hour},
+		{Type: "CAA", Name: "caa", Value: `0 issue "letsencrypt.org"`, TTL: time.Hour},
+	}
+
+	zone, actual, err := UnmarshalZone([]byte(input))
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if zone != "example.com." {
+		t.Errorf("Expected zone example.com. but got %s", zone)
+	}
+	if len(actual) != len(expect) {
+		t.Fatalf("Expected %d records but got %d: %+v", len(expect), len(actual), actual)
+	}
+	for i := range expect {
+		if actual[i] != expect[i] {
+			t.Errorf("Record %d: expected %+v\nbut got  %+v", i, expect[i], actual[i])
+		}
+	}
+}
+
+func TestUnmarshalZoneErrors(t *testing.T) {
+	for i, input := range []string{
+		"www 300 IN A 192.0.2.1\n",
+		"$ORIGIN example.com.\nwww 300 IN A\n",
+		"$ORIGIN example.com.\nwww 300 IN TXT \"unterminated\n",
+		"$ORIGIN example.com.\n@ SOA ns1 hostmaster ( 1 2 3 4 5\n",
+		"$ORIGIN example.com.\nwww.example.net. 300 IN A 192.0.2.1\n",
+		"$ORIGIN example.com.\n$INCLUDE other.zone\n",
+		"$ORIGIN example.com.\n_sip._tcp SRV 10 x 5060 sip\n",
+	} {
+		if _, _, err := UnmarshalZone([]byte(input)); err == nil {
+			t.Errorf("Test %d: expected error for input %q but got none", i, input)
+		}
+	}
+}
+
+func TestMarshalZoneRoundTrip(t *testing.T) {
+	recs := []Record{
+		{Type: "SOA", Name: "@", Value: "ns1.example.com. admin.example.com. 1 7200 3600 1209600 3600", TTL: time.Hour},
+		{Type: "NS", Name: "@", Value: "ns1.example.com.", TTL: time.Hour},
+		{Type: "A", Name: "www", Value: "192.0.2.1", TTL: 5 * time.Minute},
+		{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10, TTL: time.Hour},
+		{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 10, Weight: 20, TTL: time.Hour},
+		{Type: "TXT", Name: "dkim", Value: strings.Repeat("k", 600) + ` "quoted" \ and` + "\x00\xff", TTL: time.Hour},
+		{Type: "CAA", Name: "@", Value: `0 issue "letsencrypt.org"`, TTL: time.Hour},
+	}
+
+	data, err := MarshalZone("example.com.", recs)
+	if err != nil {
+		t.Fatalf("Marshal: expected no error but got: %v", err)
+	}
+	zone, actual, err := UnmarshalZone(data)
+	if err != nil {
+		t.Fatalf("Unmarshal: expected no error but got: %v\n%s", err, data)
+	}
+	if zone != "example.com." {
+		t.Errorf("Expected zone example.com. but got %s", zone)
+	}
+	if len(actual) != len(recs) {
+		t.Fatalf("Expected %d records but got %d", len(recs), len(actual))
+	}
+	for i := range recs {
+		if actual[i] != recs[i] {
+			t.Errorf("Record %d: expected %+v\nbut got  %+v", i, recs[i], actual[i])
+		}
+	}
+}
+
+func TestWriteZoneGolden(t *testing.T) {
+	recs := []Record{
+		{Type: "NS", Name: "@", Value: "ns1.example.com.", TTL: time.Hour},
+		{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10, TTL: time.Hour},
+		{Type: "TXT", Name: "@", Value: "v=spf1 mx -all", TTL: 5 * time.Minute},
+		{Type: "A", Name: "www", Value: "192.0.2.1", TTL: 5 * time.Minute},
+		{Type: "AAAA", Name: "www", Value: "2001:db8::1", TTL: 5 * time.Minute},
+		{Type: "SRV", Name: "_sip._tcp", Value: "5060 sip.example.com.", Priority: 10, Weight: 20, TTL: time.Hour},
+		{Type: "CAA", Name: "@", Value: `0 issue "letsencrypt.org"`, TTL: time.Hour},
+		{Type: "SOA", Name: "@", Value: "ns1.example.com. hostmaster.example.com. 2024010101 86400 7200 3600000 3600", TTL: time.Hour},
+		{Type: "NS", Name: "@", Value: "ns2.example.net.", TTL: time.Hour},
+		{Type: "MX", Name: "@", Value: "backup.example.net.", Priority: 20, TTL: time.Hour},
+	}
+
+	golden, err := os.ReadFile(filepath.Join("testdata", "example.com.zone"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteZone(&buf, "example.com.", recs); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if buf.String() != string(golden) {
+		t.Errorf("Expected:\n%s\nbut got:\n%s", golden, buf.String())
+	}
+
+	// the golden file parses back to the same records, in file order
+	_, parsed, err := UnmarshalZone(golden)
+	if err != nil {
+		t.Fatalf("Unmarshal: expected no error but got: %v", err)
+	}
+	expect := []Record{recs[7], recs[0], recs[8], recs[1], recs[9], recs[2], recs[3], recs[4], recs[5], recs[6]}
+	if !reflect.DeepEqual(parsed, expect) {
+		t.Errorf("Unmarshal: expected:\n%+v\nbut got:\n%+v", expect, parsed)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, fmt.Errorf("write failed") }
+
+func TestWriteZoneWriteError(t *testing.T) {
+	err := WriteZone(failingWriter{}, "example.com.", []Record{{Type: "A", Name: "www", Value: "192.0.2.1"}})
+	if err == nil {
+		t.Errorf("Expected write error but got none")
+	}
+}
+
+func TestParseZoneFile(t *testing.T) {
+	input := `$TTL 3600
+@	IN	SOA	ns1.example.com. hostmaster (
+		2024010101 86400 7200 3600000 300 )
+	IN	NS	ns1
+	IN	MX	10 mail
+	IN	MX	20 mail.example.net.
+mail	300	IN	A	192.0.2.25
+_submission._tcp	SRV	0 1 587 mail
+dkim._domainkey.example.com.	IN	TXT	( "v=DKIM1; k=rsa; "
+		"p=MIIBIjANBgkq" )
+$ORIGIN sub.example.com.
+www	60	A	192.0.2.80
+`
+	expect := []Record{
+		{Type: "SOA", Name: "@", Value: "ns1.example.com. hostmaster.example.com. 2024010101 86400 7200 3600000 300", TTL: time.Hour},
+		{Type: "NS", Name: "@", Value: "ns1.example.com.", TTL: time.Hour},
+		{Type: "MX", Name: "@", Value: "mail.example.com.", Priority: 10, TTL: time.Hour},
+		{Type: "MX", Name: "@", Value: "mail.example.net.", Priority: 20, TTL: time.Hour},
+		{Type: "A", Name: "mail", Value: "192.0.2.25", TTL: 5 * time.Minute},
+		{Type: "SRV", Name: "_submission._tcp", Value: "587 mail.example.com.", Priority: 0, Weight: 1, TTL: time.Hour},
+		{Type: "TXT", Name: "dkim._domainkey", Value: "v=DKIM1; k=rsa; p=MIIBIjANBgkq", TTL: time.Hour},
+		{Type: "A", Name: "www.sub", Value: "192.0.2.80", TTL: time.Minute},
+	}
+
+	for _, zone := range []string{"example.com.", "example.com"} {
+		actual, err := ParseZoneFile(strings.NewReader(input), zone)
+		if err != nil {
+			t.Fatalf("Zone %s: expected no error but got: %v", zone, err)
+		}
+		if !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Zone %s: expected:\n%+v\nbut got:\n%+v", zone, expect, actual)
+		}
+	}
+
+	if _, err := ParseZoneFile(strings.NewReader(input), "example.net."); err == nil {
+		t.Errorf("Expected error for records outside of zone but got none")
+	}
+	if _, err := ParseZoneFile(strings.NewReader(input), ""); err == nil {
+		t.Errorf("Expected error for empty zone but got none")
+	}
+}
+
+func TestUnmarshalZoneSOAUnits(t *testing.T) {
+	input := "$ORIGIN example.com.\n@ 3600 IN SOA ns1 hostmaster 2024010101 1d 2h 4w 1h\n"
+	_, recs, err := UnmarshalZone([]byte(input))
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(recs) != 1 {
+		t.Fatalf("Expected 1 record but got %+v", recs)
+	}
+	if expect := "ns1.example.com. hostmaster.example.com. 2024010101 86400 7200 2419200 3600"; recs[0].Value != expect {
+		t.Errorf("Expected value %q but got %q", expect, recs[0].Value)
+	}
+
+	soa, err := recs[0].ToSOA()
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if soa.Refresh != 24*time.Hour || soa.Retry != 2*time.Hour || soa.Expire != 4*7*24*time.Hour || soa.Minimum != time.Hour {
+		t.Errorf("Expected timers 1d 2h 4w 1h but got %+v", soa)
+	}
+	if rec := soa.ToRecord(); rec != recs[0] {
+		t.Errorf("Expected SOA to round-trip:\n%+v\nbut got:\n%+v", recs[0], rec)
+	}
+
+	if _, _, err := UnmarshalZone([]byte("$ORIGIN example.com.\n@ 3600 IN SOA ns1 hostmaster 1 1x 2h 4w 1h\n")); err == nil {
+		t.Errorf("Expected error for invalid SOA timer but got none")
+	}
+}
+
+func TestEscapeTXT(t *testing.T) {
+	for i, test := range []struct {
+		input, escaped string
+	}{
+		{input: "", escaped: ""},
+		{input: "v=spf1 -all", escaped: "v=spf1 -all"},
+		{input: `say "hi"`, escaped: `say \"hi\"`},
+		{input: `back\slash`, escaped: `back\\slash`},
+		{input: "line one\nline two", escaped: `line one\010line two`},
+		{input: "\x00\x7f\xffé", escaped: `\000\127\255\195\169`},
+	} {
+		actual := EscapeTXT(test.input)
+		if actual != test.escaped {
+			t.Errorf("Test %d: EscapeTXT(%q): expected %q but got %q", i, test.input, test.escaped, actual)
+		}
+		unescaped, err := UnescapeTXT(actual)
+		if err != nil {
+			t.Errorf("Test %d: UnescapeTXT(%q): expected no error but got: %v", i, actual, err)
+			continue
+		}
+		if unescaped != test.input {
+			t.Errorf("Test %d: UnescapeTXT(%q): expected %q but got %q", i, actual, test.input, unescaped)
+		}
+	}
+}
+
+func TestUnescapeTXT(t *testing.T) {
+	for i, test := range []struct {
+		input     string
+		expect    string
+		shouldErr bool
+	}{
+		{input: `\a\b\c`, expect: "abc"},
+		{input: `\0651`, expect: "A1"},
+		{input: `\65`, expect: "65"},
+		{input: `\256`, shouldErr: true},
+		{input: `trailing\`, shouldErr: true},
+	} {
+		actual, err := UnescapeTXT(test.input)
+		if test.shouldErr {
+			if err == nil {
+				t.Errorf("Test %d: UnescapeTXT(%q): expected error but got none", i, test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: UnescapeTXT(%q): expected no error but got: %v", i, test.input, err)
+			continue
+		}
+		if actual != test.expect {
+			t.Errorf("Test %d: UnescapeTXT(%q): expected %q but got %q", i, test.input, test.expect, actual)
+		}
+	}
+}
+
+func TestSplitTXT(t *testing.T) {
+	dkim := "v=DKIM1; k=rsa; p=" + strings.Repeat("MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8A", 19)[:582]
+
+	for i, test := range []struct {
+		text   string
+		expect []string
+	}{
+		{
+			text:   "",
+			expect: []string{""},
+		},
+		{
+			text:   "short",
+			expect: []string{"short"},
+		},
+		{
+			text:   dkim,
+			expect: []string{dkim[:255], dkim[255:510], dkim[510:]},
+		},
+		{
+			// "é" is 2 bytes and would straddle the 255-byte boundary
+			text:   strings.Repeat("a", 254) + "é" + "b",
+			expect: []string{strings.Repeat("a", 254), "éb"},
+		},
+		{
+			// "€" is 3 bytes
+			text:   strings.Repeat("a", 253) + "€€",
+			expect: []string{strings.Repeat("a", 253), "€€"},
+		},
+	} {
+		actual := SplitTXT(test.text)
+		if len(actual) != len(test.expect) {
+			t.Errorf("Test %d: expected %d chunks but got %d", i, len(test.expect), len(actual))
+			continue
+		}
+		for j := range actual {
+			if actual[j] != test.expect[j] {
+				t.Errorf("Test %d: chunk %d: expected %q but got %q", i, j, test.expect[j], actual[j])
+			}
+			if len(actual[j]) > 255 {
+				t.Errorf("Test %d: chunk %d is %d bytes long", i, j, len(actual[j]))
+			}
+		}
+		if joined := strings.Join(actual, ""); joined != test.text {
+			t.Errorf("Test %d: chunks do not join back into the input", i)
+		}
+
+		quoted := make([]string, len(actual))
+		for j, chunk := range actual {
+			quoted[j] = `"` + EscapeTXT(chunk) + `"`
+		}
+		joined, err := JoinTXT(strings.Join(quoted, " "))
+		if err != nil {
+			t.Errorf("Test %d: JoinTXT: expected no error but got: %v", i, err)
+		} else if joined != test.text {
+			t.Errorf("Test %d: JoinTXT: expected %q but got %q", i, test.text, joined)
+		}
+	}
+}
+
+func TestJoinTXT(t *testing.T) {
+	for i, test := range []struct {
+		data      string
+		expect    string
+		shouldErr bool
+	}{
+		{data: `"hello"`, expect: "hello"},
+		{data: `hello`, expect: "hello"},
+		{data: `"part one" "part two"`, expect: "part onepart two"},
+		{data: `"say \"hi\"" \059 "\195\169"`, expect: `say "hi";é`},
+		{data: `"unterminated`, shouldErr: true},
+		{data: "\"one\"\n\"two\"", shouldErr: true},
+	} {
+		actual, err := JoinTXT(test.data)
+		if test.shouldErr {
+			if err == nil {
+				t.Errorf("Test %d: expected error for %q but got none", i, test.data)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: expected no error for %q but got: %v", i, test.data, err)
+			continue
+		}
+		if actual != test.expect {
+			t.Errorf("Test %d: expected %q but got %q", i, test.expect, actual)
+		}
+	}
+}
+
+func TestSplitJoinTXTRoundTrip(t *testing.T) {
+	for i, text := range []string{
+		strings.Repeat("x", 300),
+		strings.Repeat(`a"b\c;`, 80),
+		strings.Repeat("é", 200) + "\x00\t",
+	} {
+		var quoted []string
+		for _, chunk := range SplitTXT(text) {
+			quoted = append(quoted, quoteCharacterString(chunk))
+		}
+		actual, err := JoinTXT(strings.Join(quoted, " "))
+		if err != nil {
+			t.Errorf("Test %d: expected no error but got: %v", i, err)
+			continue
+		}
+		if actual != text {
+			t.Errorf("Test %d: expected %q but got %q", i, text, actual)
+		}
+	}
+}
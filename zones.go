@@ -0,0 +1,55 @@
+package libdns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ZoneForName returns the zone among zones that contains fqdn. If more
+// than one zone contains fqdn, such as "example.com." and
+// "sub.example.com." for "www.sub.example.com.", the most specific
+// (longest) one is returned. Names are compared as by RelativeName. It
+// returns false if no zone contains fqdn.
+func ZoneForName(fqdn string, zones []Zone) (Zone, bool) {
+	trimmed := strings.TrimSuffix(fqdn, ".")
+	var best Zone
+	bestLabels := -1
+	for _, z := range zones {
+		zoneName := strings.TrimSuffix(z.Name, ".")
+		if _, ok := relativeName(trimmed, zoneName); !ok {
+			continue
+		}
+		labels := 0
+		if zoneName != "" {
+			labels = strings.Count(zoneName, ".") + 1
+		}
+		if labels > bestLabels {
+			best, bestLabels = z, labels
+		}
+	}
+	return best, bestLabels >= 0
+}
+
+// RelativeToMatchingZone finds the zone that contains fqdn among the
+// zones listed by zl, as by ZoneForName, and returns that zone's name
+// along with fqdn made relative to it. If fqdn is the apex of the zone,
+// the relative name is "@".
+//
+// It returns an error wrapping ErrZoneNotFound if no listed zone
+// contains fqdn.
+func RelativeToMatchingZone(ctx context.Context, zl ZoneLister, fqdn string) (zone, relName string, err error) {
+	zones, err := zl.ListZones(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("listing zones: %w", err)
+	}
+	z, ok := ZoneForName(fqdn, zones)
+	if !ok {
+		return "", "", fmt.Errorf("no zone found for %s: %w", fqdn, ErrZoneNotFound)
+	}
+	relName = RelativeName(fqdn, z.Name)
+	if relName == "" {
+		relName = "@"
+	}
+	return z.Name, relName, nil
+}
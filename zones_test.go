@@ -0,0 +1,95 @@
+package libdns
+
+import (
+	"context"
+	"testing"
+)
+
+type staticZoneLister []Zone
+
+func (zl staticZoneLister) ListZones(ctx context.Context) ([]Zone, error) {
+	return zl, nil
+}
+
+func TestZoneForName(t *testing.T) {
+	zones := []Zone{
+		{Name: "example.com."},
+		{Name: "sub.example.com.", Kind: "primary"},
+		{Name: "example.net"},
+	}
+
+	for i, test := range []struct {
+		fqdn     string
+		expect   string
+		expectOK bool
+	}{
+		{fqdn: "www.example.com.", expect: "example.com.", expectOK: true},
+		{fqdn: "www.sub.example.com.", expect: "sub.example.com.", expectOK: true},
+		{fqdn: "sub.example.com", expect: "sub.example.com.", expectOK: true},
+		{fqdn: "Example.NET.", expect: "example.net", expectOK: true},
+		{fqdn: "fooexample.com.", expectOK: false},
+		{fqdn: "example.org.", expectOK: false},
+	} {
+		actual, ok := ZoneForName(test.fqdn, zones)
+		if ok != test.expectOK || actual.Name != test.expect {
+			t.Errorf("Test %d: FQDN=%s - expected (%s, %t) but got (%s, %t)",
+				i, test.fqdn, test.expect, test.expectOK, actual.Name, ok)
+		}
+	}
+
+	// zones in the other order must give the same result
+	if actual, _ := ZoneForName("www.sub.example.com.", []Zone{zones[1], zones[0]}); actual != zones[1] {
+		t.Errorf("Expected most specific zone %+v but got %+v", zones[1], actual)
+	}
+
+	// the root zone contains every name, but is the least specific
+	root := []Zone{{Name: "."}, {Name: "example.com."}}
+	if actual, ok := ZoneForName("www.example.org.", root); !ok || actual.Name != "." {
+		t.Errorf("Expected root zone but got (%s, %t)", actual.Name, ok)
+	}
+	if actual, ok := ZoneForName("www.example.com.", root); !ok || actual.Name != "example.com." {
+		t.Errorf("Expected example.com. but got (%s, %t)", actual.Name, ok)
+	}
+}
+
+func TestRelativeToMatchingZone(t *testing.T) {
+	zl := staticZoneLister{
+		{Name: "example.com."},
+		{Name: "sub.example.com."},
+		{Name: "example.net."},
+		{Name: "ample.com."},
+	}
+
+	for i, test := range []struct {
+		fqdn       string
+		expectZone string
+		expectRel  string
+		shouldErr  bool
+	}{
+		{fqdn: "www.example.com.", expectZone: "example.com.", expectRel: "www"},
+		{fqdn: "www.sub.example.com.", expectZone: "sub.example.com.", expectRel: "www"},
+		{fqdn: "a.b.sub.example.com", expectZone: "sub.example.com.", expectRel: "a.b"},
+		{fqdn: "sub.example.com.", expectZone: "sub.example.com.", expectRel: "@"},
+		{fqdn: "example.com.", expectZone: "example.com.", expectRel: "@"},
+		{fqdn: "WWW.Example.NET.", expectZone: "example.net.", expectRel: "WWW"},
+		{fqdn: "www.ample.com.", expectZone: "ample.com.", expectRel: "www"},
+		{fqdn: "www.example.org.", shouldErr: true},
+		{fqdn: "com.", shouldErr: true},
+	} {
+		zone, rel, err := RelativeToMatchingZone(context.Background(), zl, test.fqdn)
+		if test.shouldErr {
+			if err == nil {
+				t.Errorf("Test %d: expected error for %s but got zone=%s rel=%s", i, test.fqdn, zone, rel)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: expected no error but got: %v", i, err)
+			continue
+		}
+		if zone != test.expectZone || rel != test.expectRel {
+			t.Errorf("Test %d: FQDN=%s - expected zone=%s rel=%s but got zone=%s rel=%s",
+				i, test.fqdn, test.expectZone, test.expectRel, zone, rel)
+		}
+	}
+}